@@ -5,6 +5,7 @@ import (
 	"log"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // chats can pick a TIMEZONE even where the system has no zoneinfo
 
 	"bigbrother/internal/bot"
 	"bigbrother/internal/config"
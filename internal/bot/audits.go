@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/settings"
+)
+
+func (h *Handler) handleAudits(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return h.replyText(msg.Chat.ID, h.formatAudits(msg.Chat.ID, p))
+	}
+	if len(args) != 2 || (args[0] != "on" && args[0] != "off") {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Usage: /audits on|off <name>"))
+	}
+
+	audit, ok := h.registry.Lookup(args[1])
+	if !ok {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Unknown audit: %s", args[1]))
+	}
+	_, err := h.settings.Update(msg.Chat.ID, func(o *settings.Overrides, current settings.Settings) error {
+		audits := toggleAudit(current.Audits, audit.Name(), args[0] == "on")
+		o.Audits = &audits
+		return nil
+	})
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to save the settings."))
+		return err
+	}
+	return h.replyText(msg.Chat.ID, h.formatAudits(msg.Chat.ID, p))
+}
+
+func (h *Handler) formatAudits(chatID int64, p i18n.Printer) string {
+	enabled := make(map[string]bool)
+	for _, name := range h.chatSettings(chatID).Audits {
+		enabled[name] = true
+	}
+
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Audits for this chat:\n"))
+	for _, audit := range h.registry.Audits() {
+		mark := "▫️"
+		if enabled[audit.Name()] {
+			mark = "✅"
+		}
+		b.WriteString(fmt.Sprintf("%s %s - %s\n", mark, audit.Name(), p.Text(audit.Title())))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"bigbrother/internal/config"
+	"bigbrother/internal/processor"
+	"bigbrother/internal/storage"
 )
 
 func Run(ctx context.Context, cfg config.Config) error {
@@ -24,7 +27,39 @@ func Run(ctx context.Context, cfg config.Config) error {
 	api.Debug = false
 	log.Printf("Authorized as @%s", api.Self.UserName)
 
-	handler := NewHandler(api, cfg.DataDir, cfg.MaxFileBytes, cfg.MaxDocsPerMinuteChat)
+	registry, err := buildRegistry(cfg)
+	if err != nil {
+		return err
+	}
+
+	shifts, err := processor.ParseShifts(cfg.Shifts)
+	if err != nil {
+		return fmt.Errorf("invalid SHIFTS: %w", err)
+	}
+
+	profiles, err := processor.ParseColumnProfiles(cfg.ColumnProfiles)
+	if err != nil {
+		return fmt.Errorf("invalid COLUMN_PROFILES: %w", err)
+	}
+
+	phrases, err := buildPhrasebook(cfg)
+	if err != nil {
+		return err
+	}
+
+	backend, err := newBackend(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.EncryptionRotate {
+		if err := rotateKeys(ctx, backend); err != nil {
+			return err
+		}
+	}
+
+	handler := NewHandler(api, cfg, registry, shifts, profiles, phrases, backend)
+	go handler.archive.RunJanitor(ctx, time.Hour, cfg.ArchiveMaxAge, cfg.ArchiveMaxBytes)
 
 	updateCfg := tgbotapi.NewUpdate(0)
 	updateCfg.Timeout = 30
@@ -45,3 +80,82 @@ func Run(ctx context.Context, cfg config.Config) error {
 		}
 	}
 }
+
+func newBackend(cfg config.Config) (storage.Store, error) {
+	var backend storage.Store = storage.NewLocalStore(cfg.DataDir)
+	if cfg.StorageBackend == "s3" {
+		s3, err := storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create S3 store: %w", err)
+		}
+		backend = s3
+	}
+	if cfg.EncryptionKeys == "" {
+		if cfg.EncryptionRequire {
+			return nil, errors.New("ENCRYPTION_REQUIRE needs ENCRYPTION_KEYS or ENCRYPTION_KEY_FILE")
+		}
+		return backend, nil
+	}
+	keys, err := storage.ParseKeyring(cfg.EncryptionKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption keys: %w", err)
+	}
+	encrypted := storage.NewEncryptedStore(backend, keys)
+	encrypted.RequireEncrypted = cfg.EncryptionRequire
+	return encrypted, nil
+}
+
+// rotateKeys re-encrypts stored reports and archived uploads with the
+// current key. It runs before any update is handled.
+func rotateKeys(ctx context.Context, backend storage.Store) error {
+	encrypted, ok := backend.(*storage.EncryptedStore)
+	if !ok {
+		return errors.New("ENCRYPTION_ROTATE needs ENCRYPTION_KEYS or ENCRYPTION_KEY_FILE")
+	}
+	for _, prefix := range []string{"reports/", "archive/"} {
+		n, err := encrypted.Rotate(ctx, prefix)
+		if err != nil {
+			return fmt.Errorf("rotate %s: %w", prefix, err)
+		}
+		log.Printf("Re-encrypted %d objects under %s", n, prefix)
+	}
+	return nil
+}
+
+func buildRegistry(cfg config.Config) (*processor.Registry, error) {
+	vatRules := processor.DefaultVATRules()
+	if cfg.VATRates != "" {
+		rules, err := processor.ParseVATRules(cfg.VATRates)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VAT_RATES: %w", err)
+		}
+		vatRules = rules
+	}
+
+	registry := processor.NewRegistry(
+		processor.NewBottlesAudit(),
+		processor.NewSequenceAudit(),
+		processor.NewVATAudit(vatRules),
+	)
+	if cfg.OpeningHours != "" {
+		schedule, err := processor.ParseSchedule(cfg.OpeningHours, cfg.Holidays)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OPENING_HOURS/HOLIDAYS: %w", err)
+		}
+		if err := registry.Register(processor.NewAfterHoursAudit(schedule)); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range cfg.Audits {
+		if _, ok := registry.Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown audit in AUDITS: %s", name)
+		}
+	}
+	return registry, nil
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"errors"
+	"image"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/chart"
+	"bigbrother/internal/history"
+	"bigbrother/internal/processor"
+)
+
+const chartUsage = "Usage: /chart [days|hours|heatmap] [7d|30d|from..to|#report]"
+
+func (h *Handler) handleChart(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	usage := p.Sprintf(chartUsage)
+	kind := "days"
+	rangeArg := "30d"
+	reportID := 0
+	for _, arg := range strings.Fields(strings.ToLower(msg.CommandArguments())) {
+		switch {
+		case arg == "days" || arg == "hours" || arg == "heatmap":
+			kind = arg
+		case strings.HasPrefix(arg, "#"):
+			n, err := strconv.Atoi(arg[1:])
+			if err != nil || n <= 0 {
+				return h.replyText(msg.Chat.ID, usage)
+			}
+			reportID = n
+		default:
+			rangeArg = arg
+		}
+	}
+
+	var records []history.Record
+	var from, to time.Time
+	if reportID > 0 {
+		rec, err := h.history.Get(msg.Chat.ID, reportID)
+		if errors.Is(err, history.ErrNotFound) {
+			return h.replyText(msg.Chat.ID, p.Sprintf("Report #%d not found.", reportID))
+		}
+		if err != nil {
+			_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load the report."))
+			return err
+		}
+		records = []history.Record{rec}
+		if err := h.applyResolutions(msg.Chat.ID, records...); err != nil {
+			return err
+		}
+		from, to = history.Span(records)
+	} else {
+		var err error
+		from, to, err = parseStatsRange(rangeArg, h.chatNow(msg.Chat.ID), p)
+		if err != nil {
+			return h.replyText(msg.Chat.ID, err.Error()+"\n"+usage)
+		}
+		records, err = h.loadHistory(msg.Chat.ID)
+		if err != nil {
+			_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load report history."))
+			return err
+		}
+	}
+
+	receipts := history.Receipts(records, from, to)
+	if len(receipts) == 0 {
+		return h.replyText(msg.Chat.ID, p.Sprintf("No checked receipts to chart."))
+	}
+
+	var img image.Image
+	switch kind {
+	case "hours":
+		img = chart.LitersPerHour(receipts)
+	case "heatmap":
+		img = chart.MismatchHeatmap(receipts)
+	default:
+		img = chart.MismatchesPerDay(history.Summarize(records, from, to))
+	}
+	return h.sendChart(msg.Chat.ID, kind, img)
+}
+
+// sendReportChart attaches the liters-per-hour chart of a fresh upload.
+func (h *Handler) sendReportChart(chatID int64, report processor.Report) error {
+	if len(report.Receipts) == 0 {
+		return nil
+	}
+	return h.sendChart(chatID, "hours", chart.LitersPerHour(report.Receipts))
+}
+
+func (h *Handler) sendChart(chatID int64, name string, img image.Image) error {
+	data, err := chart.EncodePNG(img)
+	if err != nil {
+		return err
+	}
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: name + ".png", Bytes: data})
+	_, err = h.api.Send(photo)
+	return err
+}
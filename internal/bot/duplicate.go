@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/history"
+	"bigbrother/internal/i18n"
+)
+
+const (
+	duplicateCallbackPrefix = "dup:"
+	duplicateShow           = "show"
+	duplicateForce          = "force"
+)
+
+// pendingUploads remembers the upload behind a duplicate prompt so it can be
+// processed again on request. Like pendingComments it lives in memory only.
+type pendingUploads struct {
+	mu        sync.Mutex
+	byMessage map[pendingKey]upload
+}
+
+func newPendingUploads() *pendingUploads {
+	return &pendingUploads{byMessage: make(map[pendingKey]upload)}
+}
+
+func (p *pendingUploads) Set(chatID int64, messageID int, up upload) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.byMessage[pendingKey{chatID, messageID}] = up
+}
+
+func (p *pendingUploads) Take(chatID int64, messageID int) (upload, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := pendingKey{chatID, messageID}
+	up, ok := p.byMessage[key]
+	delete(p.byMessage, key)
+	return up, ok
+}
+
+func (h *Handler) sendDuplicatePrompt(chatID int64, prev history.Record, up upload) error {
+	p := i18n.For(h.language(chatID, up.LanguageCode))
+	text := p.Sprintf("This file was already processed on %s (report #%d).", h.formatTime(chatID, prev.CreatedAt), prev.ID)
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Show report"), duplicateCallbackPrefix+duplicateShow+":"+strconv.Itoa(prev.ID)),
+		tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Process again"), duplicateCallbackPrefix+duplicateForce),
+	))
+	sent, err := h.api.Send(reply)
+	if err != nil {
+		return err
+	}
+	h.uploads.Set(chatID, sent.MessageID, up)
+	return nil
+}
+
+func (h *Handler) handleDuplicateCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) error {
+	chatID := cb.Message.Chat.ID
+	action, arg, _ := strings.Cut(strings.TrimPrefix(cb.Data, duplicateCallbackPrefix), ":")
+	_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, ""))
+	p := h.printer(chatID, cb.From)
+
+	switch action {
+	case duplicateShow:
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil
+		}
+		rec, err := h.history.Get(chatID, id)
+		if errors.Is(err, history.ErrNotFound) {
+			return h.replyText(chatID, p.Sprintf("Report #%d not found.", id))
+		}
+		if err != nil {
+			return err
+		}
+		if err := h.applyResolutions(chatID, rec); err != nil {
+			return err
+		}
+		rec.Report.ApplyShifts(h.shifts)
+		return h.replyReport(chatID, rec.Report, p.Sprintf("Report #%d", rec.ID))
+	case duplicateForce:
+		up, ok := h.uploads.Take(chatID, cb.Message.MessageID)
+		if !ok {
+			return h.replyText(chatID, p.Sprintf("This upload is no longer available. Please send the file again."))
+		}
+		return h.processUpload(ctx, chatID, up, true)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/history"
+)
+
+func (h *Handler) handleExport(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	usage := p.Sprintf("Usage: /export [xlsx|json] [report number]")
+	format := "xlsx"
+	id := 0
+	for _, arg := range strings.Fields(strings.ToLower(msg.CommandArguments())) {
+		switch arg {
+		case "xlsx", "json":
+			format = arg
+		default:
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+			if err != nil || n <= 0 {
+				return h.replyText(msg.Chat.ID, usage)
+			}
+			id = n
+		}
+	}
+
+	rec, err := h.loadRecord(msg.Chat.ID, id)
+	if errors.Is(err, history.ErrNotFound) {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Nothing to export. Upload a file first or check the report number."))
+	}
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load the report."))
+		return err
+	}
+
+	var buf bytes.Buffer
+	if format == "json" {
+		err = rec.Report.WriteJSON(&buf)
+	} else {
+		err = rec.Report.WriteXLSX(&buf)
+	}
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to export the report."))
+		return fmt.Errorf("export %s: %w", format, err)
+	}
+
+	name := fmt.Sprintf("report_%d_%s.%s", rec.ID, rec.CreatedAt.Format("20060102_150405"), format)
+	doc := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FileBytes{Name: name, Bytes: buf.Bytes()})
+	_, err = h.api.Send(doc)
+	return err
+}
+
+// loadRecord returns report id of the chat, or the latest one when id is zero.
+func (h *Handler) loadRecord(chatID int64, id int) (history.Record, error) {
+	if id == 0 {
+		return h.history.Latest(chatID)
+	}
+	return h.history.Get(chatID, id)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,27 +14,97 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"bigbrother/internal/config"
+	"bigbrother/internal/history"
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/inventory"
 	"bigbrother/internal/processor"
+	"bigbrother/internal/settings"
 	"bigbrother/internal/storage"
 )
 
+const helpText = `Upload an .xlsx or .csv document. I will download and process it.
+
+/audits - list audits for this chat
+/audits on|off <name> - toggle an audit
+/settings - tolerance, snark, tone, time zone, audits, columns and language for this chat
+/snark - phrases of this chat, /snark add <kind> <text> to add one
+/tap <product> [<liters>l] - tap a new keg
+/kegs - keg status
+/stock - bottle stock
+/stock <size> <count|+delivered> [threshold] - set stock
+/count <size> <counted> - reconcile with a physical count
+/export [xlsx|json] [number] - download a report
+/operators [min receipts] - mismatch rate per operator
+/stats [7d|30d|from..to] - trends over stored reports
+/chart [days|hours|heatmap] [7d|30d|from..to|#report] - chart image
+/receipt <number> - look up a receipt in stored reports
+/resolve <number> - mark a mismatch as resolved
+/resolutions - who resolved what and when`
+
 type Handler struct {
 	api          *tgbotapi.BotAPI
 	dataDir      string
 	maxFileBytes int64
 	limiter      *rateLimiter
+	registry     *processor.Registry
+	shifts       []processor.Shift
+	settings     *settings.Store
+	profiles     []processor.ColumnProfile
+	phrases      *processor.Phrasebook
+	inventory    *inventory.Store
+	history      *history.Store
+	resolutions  *history.ResolutionStore
+	comments     *pendingComments
+	uploads      *pendingUploads
+	archive      *storage.Archive
+	downloader   *storage.Downloader
+
+	operatorMinReceipts int
+	reportCharts        bool
 }
 
-func NewHandler(api *tgbotapi.BotAPI, dataDir string, maxFileBytes int64, maxDocsPerMinuteChat int) *Handler {
+func NewHandler(api *tgbotapi.BotAPI, cfg config.Config, registry *processor.Registry, shifts []processor.Shift, profiles []processor.ColumnProfile, phrases *processor.Phrasebook, backend storage.Store) *Handler {
+	defaults := cfg.Audits
+	if len(defaults) == 0 {
+		for _, audit := range registry.Audits() {
+			defaults = append(defaults, audit.Name())
+		}
+	}
 	return &Handler{
 		api:          api,
-		dataDir:      dataDir,
-		maxFileBytes: maxFileBytes,
-		limiter:      newRateLimiter(maxDocsPerMinuteChat, time.Minute),
+		dataDir:      cfg.DataDir,
+		maxFileBytes: cfg.MaxFileBytes,
+		limiter:      newRateLimiter(cfg.MaxDocsPerMinuteChat, time.Minute),
+		registry:     registry,
+		shifts:       shifts,
+		settings: settings.NewStore(cfg.DataDir, settings.Settings{
+			ToleranceML: cfg.ToleranceML,
+			Snark:       cfg.Snark,
+			Tone:        cfg.Tone,
+			Timezone:    cfg.Timezone,
+			Audits:      defaults,
+			Profile:     processor.DefaultProfile.Name,
+		}),
+		profiles:    append([]processor.ColumnProfile{processor.DefaultProfile}, profiles...),
+		phrases:     phrases,
+		inventory:   inventory.NewStore(cfg.DataDir, cfg.BottleLowStock),
+		history:     history.NewStore(backend),
+		resolutions: history.NewResolutionStore(cfg.DataDir),
+		comments:    newPendingComments(),
+		uploads:     newPendingUploads(),
+		archive:     storage.NewArchive(backend),
+		downloader:  storage.NewDownloader(),
+
+		operatorMinReceipts: cfg.OperatorMinReceipts,
+		reportCharts:        cfg.ReportCharts,
 	}
 }
 
 func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) error {
+	if update.CallbackQuery != nil {
+		return h.handleCallback(ctx, update.CallbackQuery)
+	}
 	if update.Message == nil {
 		return nil
 	}
@@ -42,6 +115,10 @@ func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) erro
 		return h.handleCommand(msg)
 	}
 
+	if handled, err := h.handleComment(msg); handled {
+		return err
+	}
+
 	if msg.Document != nil {
 		return h.handleDocument(ctx, msg)
 	}
@@ -50,13 +127,42 @@ func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) erro
 }
 
 func (h *Handler) handleCommand(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
 	switch msg.Command() {
 	case "start":
-		return h.replyText(msg.Chat.ID, "Send me an .xlsx or .csv file and I will process it.")
+		return h.replyText(msg.Chat.ID, p.Sprintf("Send me an .xlsx or .csv file and I will process it."))
 	case "help":
-		return h.replyText(msg.Chat.ID, "Upload an .xlsx or .csv document. I will download and process it.")
+		return h.replyText(msg.Chat.ID, p.Text(helpText))
+	case "audits":
+		return h.handleAudits(msg)
+	case "settings":
+		return h.handleSettings(msg)
+	case "snark":
+		return h.handleSnark(msg)
+	case "tap":
+		return h.handleTap(msg)
+	case "kegs":
+		return h.handleKegs(msg)
+	case "stock":
+		return h.handleStock(msg)
+	case "count":
+		return h.handleCount(msg)
+	case "export":
+		return h.handleExport(msg)
+	case "operators":
+		return h.handleOperators(msg)
+	case "stats":
+		return h.handleStats(msg)
+	case "chart":
+		return h.handleChart(msg)
+	case "receipt":
+		return h.handleReceipt(msg)
+	case "resolve":
+		return h.handleResolve(msg)
+	case "resolutions":
+		return h.handleResolutions(msg)
 	default:
-		return h.replyText(msg.Chat.ID, "Unknown command. Use /help.")
+		return h.replyText(msg.Chat.ID, p.Sprintf("Unknown command. Use /help."))
 	}
 }
 
@@ -65,6 +171,7 @@ func (h *Handler) handleDocument(ctx context.Context, msg *tgbotapi.Message) err
 	if doc == nil {
 		return nil
 	}
+	p := h.printer(msg.Chat.ID, msg.From)
 
 	if h.limiter != nil {
 		if ok, retryAfter := h.limiter.Allow(msg.Chat.ID); !ok {
@@ -72,7 +179,7 @@ func (h *Handler) handleDocument(ctx context.Context, msg *tgbotapi.Message) err
 			if seconds < 1 {
 				seconds = 1
 			}
-			return h.replyText(msg.Chat.ID, fmt.Sprintf("Too many uploads. Try again in %ds.", seconds))
+			return h.replyText(msg.Chat.ID, p.Sprintf("Too many uploads. Try again in %ds.", seconds))
 		}
 	}
 
@@ -83,17 +190,42 @@ func (h *Handler) handleDocument(ctx context.Context, msg *tgbotapi.Message) err
 
 	ext := strings.ToLower(filepath.Ext(name))
 	if ext != ".xlsx" && ext != ".csv" {
-		return h.replyText(msg.Chat.ID, "Please upload a .xlsx or .csv file.")
+		return h.replyText(msg.Chat.ID, p.Sprintf("Please upload a .xlsx or .csv file."))
 	}
 
 	if h.maxFileBytes > 0 && doc.FileSize > 0 && int64(doc.FileSize) > h.maxFileBytes {
-		return h.replyText(msg.Chat.ID, fmt.Sprintf("File is too large (%d bytes). Max allowed is %d bytes.", doc.FileSize, h.maxFileBytes))
+		return h.replyText(msg.Chat.ID, p.Sprintf("File is too large (%d bytes). Max allowed is %d bytes.", doc.FileSize, h.maxFileBytes))
 	}
 
+	up := upload{FileID: doc.FileID, Name: filepath.Base(name)}
+	if msg.From != nil {
+		up.UploaderID = msg.From.ID
+		up.Uploader = userLabel(msg.From)
+		up.LanguageCode = msg.From.LanguageCode
+	}
+	return h.processUpload(ctx, msg.Chat.ID, up, false)
+}
+
+// upload is a document sent to the chat, enough to download it again.
+type upload struct {
+	FileID     string
+	Name       string
+	UploaderID int64
+	Uploader   string
+	// LanguageCode is the language of the uploader's Telegram client.
+	LanguageCode string
+}
+
+// processUpload downloads, processes and reports an upload. Unless force is
+// set, a file already processed in the chat is not processed again; with
+// force, its earlier report is replaced.
+func (h *Handler) processUpload(ctx context.Context, chatID int64, up upload, force bool) error {
 	docCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
+	lang := h.language(chatID, up.LanguageCode)
+	p := i18n.For(lang)
 
-	file, err := h.api.GetFile(tgbotapi.FileConfig{FileID: doc.FileID})
+	file, err := h.api.GetFile(tgbotapi.FileConfig{FileID: up.FileID})
 	if err != nil {
 		return fmt.Errorf("get file: %w", err)
 	}
@@ -103,38 +235,145 @@ func (h *Handler) handleDocument(ctx context.Context, msg *tgbotapi.Message) err
 		return errors.New("empty file download URL")
 	}
 
-	savedPath, err := storage.SaveIncomingFile(docCtx, storage.SaveInput{
+	saved, err := storage.SaveIncomingFile(docCtx, storage.SaveInput{
 		FileURL:      fileURL,
 		DataDir:      h.dataDir,
-		ChatID:       msg.Chat.ID,
-		OriginalName: filepath.Base(name),
+		ChatID:       chatID,
+		OriginalName: up.Name,
 		MaxBytes:     h.maxFileBytes,
+		Downloader:   h.downloader,
 	})
 	if err != nil {
-		_ = h.replyText(msg.Chat.ID, "Failed to download the file.")
+		_ = h.replyText(chatID, p.Sprintf("Failed to download the file."))
 		return fmt.Errorf("save incoming file: %w", err)
 	}
-	defer func() { _ = os.Remove(savedPath) }()
+	defer func() { _ = os.Remove(saved.Path) }()
+
+	prev, err := h.history.FindByHash(chatID, saved.SHA256)
+	if err != nil && !errors.Is(err, history.ErrNotFound) {
+		_ = h.replyText(chatID, p.Sprintf("Failed to process the file."))
+		return fmt.Errorf("find duplicate: %w", err)
+	}
+	again := err == nil
+	if again && !force {
+		return h.sendDuplicatePrompt(chatID, prev, up)
+	}
 
-	report, err := processor.ProcessFile(savedPath)
+	cs := h.chatSettings(chatID)
+	report, err := processor.ProcessFileProfile(saved.Path, h.columnProfile(cs.Profile))
+	if reason, ok := processor.IsRejected(err); ok {
+		_ = h.replyText(chatID, p.Sprintf("File rejected: %s.", reason))
+		return nil
+	}
 	if err != nil {
-		_ = h.replyText(msg.Chat.ID, "Failed to process the file.")
-		return fmt.Errorf("process xlsx: %w", err)
+		_ = h.replyText(chatID, p.Sprintf("Failed to process the file."))
+		return fmt.Errorf("process file: %w", err)
+	}
+	report.Lang = lang
+	report.ApplyTolerance(cs.ToleranceML)
+	report.ApplyAudits(h.registry, cs.Audits)
+	if resolved, err := h.resolutions.Resolved(chatID); err == nil {
+		report.ApplyResolutions(resolved)
 	}
+	report.ApplyShifts(h.shifts)
+	report.ApplyPhrases(h.phrases, cs.Tone, cs.Phrases)
 
-	if err := h.replyText(msg.Chat.ID, report.FormatText()); err != nil {
+	// Processing a file again replaces its report; the upload is already
+	// archived and booked against the inventory.
+	rec := history.Record{
+		ChatID:    chatID,
+		CreatedAt: time.Now(),
+		FileName:  up.Name,
+		SHA256:    saved.SHA256,
+		Report:    report,
+	}
+	var saveErr, archiveErr error
+	if again {
+		rec.ID = prev.ID
+		rec, saveErr = h.history.Replace(rec)
+	} else {
+		rec, saveErr = h.history.Save(rec)
+		_, archiveErr = h.archive.Add(ctx, saved.Path, storage.ArchiveMeta{
+			ChatID:       chatID,
+			UploaderID:   up.UploaderID,
+			Uploader:     up.Uploader,
+			OriginalName: up.Name,
+			SHA256:       saved.SHA256,
+			ReportID:     rec.ID,
+		})
+	}
+
+	footer := ""
+	if saveErr == nil {
+		footer = p.Sprintf("Report #%d", rec.ID)
+	}
+	if err := h.replyReport(chatID, report, footer); err != nil {
 		return err
 	}
+	if remark := h.remark(report, cs); remark != "" {
+		_ = h.replyText(chatID, remark)
+	}
+	if h.reportCharts {
+		_ = h.sendReportChart(chatID, report)
+	}
 
-	if snark := report.MismatchSnarkText(); snark != "" {
-		_ = h.replyText(msg.Chat.ID, snark)
+	if !again {
+		alerts, err := h.updateInventory(chatID, report, saved.SHA256, p)
+		if err != nil {
+			return err
+		}
+		if len(alerts) > 0 {
+			_ = h.replyText(chatID, strings.Join(alerts, "\n"))
+		}
 	}
 
+	if saveErr != nil {
+		return fmt.Errorf("save report: %w", saveErr)
+	}
+	if archiveErr != nil {
+		return fmt.Errorf("archive upload: %w", archiveErr)
+	}
 	return nil
 }
 
+func (h *Handler) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) error {
+	if cb.Message == nil {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(cb.Data, resolveCallbackPrefix):
+		return h.handleResolveCallback(cb)
+	case strings.HasPrefix(cb.Data, duplicateCallbackPrefix):
+		return h.handleDuplicateCallback(ctx, cb)
+	case strings.HasPrefix(cb.Data, settingsCallbackPrefix):
+		return h.handleSettingsCallback(cb)
+	default:
+		return nil
+	}
+}
+
 func (h *Handler) replyText(chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	_, err := h.api.Send(msg)
 	return err
 }
+
+// replyReport sends a report in HTML parse mode, followed by footer. When
+// Telegram rejects the markup, the plain text version is sent instead.
+func (h *Handler) replyReport(chatID int64, report processor.Report, footer string) error {
+	markup, text := report.FormatHTML(), report.FormatText()
+	if footer != "" {
+		markup += "\n\n" + html.EscapeString(footer)
+		text += "\n\n" + footer
+	}
+
+	msg := tgbotapi.NewMessage(chatID, markup)
+	msg.ParseMode = tgbotapi.ModeHTML
+	_, err := h.api.Send(msg)
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+		log.Printf("chat %d: report markup rejected, sending plain text: %v", chatID, err)
+		return h.replyText(chatID, text)
+	}
+	return err
+}
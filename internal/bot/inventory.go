@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/inventory"
+	"bigbrother/internal/processor"
+)
+
+func (h *Handler) handleTap(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	usage := p.Sprintf("Usage: /tap <product> [<liters>l]")
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+
+	args, sizeML := splitKegSize(args)
+	if len(args) == 0 {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+
+	product := strings.Join(args, " ")
+	keg, replaced, err := h.inventory.Tap(msg.Chat.ID, product, sizeML, time.Now())
+	if errors.Is(err, inventory.ErrUnknownKegSize) {
+		return h.replyText(msg.Chat.ID, p.Sprintf("The keg size for %s is unknown. Use /tap %s <liters>l.", product, product))
+	}
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to save the keg."))
+		return fmt.Errorf("tap keg: %w", err)
+	}
+
+	text := p.Sprintf("🛢️ Tapped %s (%.1fL).", keg.Product, float64(keg.SizeML)/1000.0)
+	if replaced != nil {
+		text += p.Sprintf("\nPrevious keg: %.1fL sold of %.1fL.", float64(replaced.SoldML)/1000.0, float64(replaced.SizeML)/1000.0)
+	}
+	return h.replyText(msg.Chat.ID, text)
+}
+
+// splitKegSize takes the keg size off the end of the /tap arguments. Only a
+// last word with a unit is a size, so "Kozel 11" stays a product name.
+func splitKegSize(args []string) ([]string, int64) {
+	last := args[len(args)-1]
+	if !strings.HasSuffix(strings.ToLower(last), "l") {
+		return args, 0
+	}
+	liters, err := parseLiters(last)
+	if err != nil {
+		return args, 0
+	}
+	return args[:len(args)-1], liters
+}
+
+func (h *Handler) handleKegs(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	kegs, err := h.inventory.Kegs(msg.Chat.ID)
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load kegs."))
+		return fmt.Errorf("load kegs: %w", err)
+	}
+	return h.replyText(msg.Chat.ID, inventory.FormatKegs(kegs, p))
+}
+
+// updateInventory books the report of the upload with the given SHA-256
+// against the chat inventory and returns alert lines printed with p.
+func (h *Handler) updateInventory(chatID int64, report processor.Report, sha256 string, p i18n.Printer) ([]string, error) {
+	loc := h.chatSettings(chatID).Location()
+	var sales []inventory.Sale
+	for _, rec := range report.Parsed {
+		// Receipt times carry no zone; they are the chat's wall clock.
+		var issued time.Time
+		if !rec.Issued.IsZero() {
+			t := rec.Issued
+			issued = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+		}
+		for _, line := range rec.Lines {
+			if line.BeerML != 0 {
+				sales = append(sales, inventory.Sale{Category: line.Category, Product: line.Product, ML: line.BeerML, Issued: issued})
+			}
+		}
+	}
+
+	alerts, err := h.inventory.Deplete(chatID, sha256, sales)
+	if err != nil {
+		return nil, fmt.Errorf("deplete kegs: %w", err)
+	}
+	low, err := h.inventory.SellBottles(chatID, report.BottleSales())
+	if err != nil {
+		return nil, fmt.Errorf("sell bottles: %w", err)
+	}
+
+	lines := make([]string, 0, len(alerts)+len(low))
+	for _, alert := range alerts {
+		lines = append(lines, alert.Format(p))
+	}
+	for _, level := range low {
+		lines = append(lines, level.FormatAlert(p))
+	}
+	return lines, nil
+}
+
+func (h *Handler) handleStock(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		levels, err := h.inventory.Bottles(msg.Chat.ID)
+		if err != nil {
+			_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load bottle stock."))
+			return fmt.Errorf("load bottles: %w", err)
+		}
+		return h.replyText(msg.Chat.ID, inventory.FormatBottles(levels, p))
+	}
+
+	usage := p.Sprintf("Usage: /stock <size> <count|+delivered> [threshold], e.g. /stock 0.5 120 20")
+	if len(args) < 2 || len(args) > 3 {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+	sizeML, err := parseLiters(args[0])
+	if err != nil {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+	add := strings.HasPrefix(args[1], "+")
+	count, err := strconv.ParseInt(strings.TrimPrefix(args[1], "+"), 10, 64)
+	if err != nil || count < 0 {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+	var threshold int64
+	if len(args) == 3 {
+		threshold, err = strconv.ParseInt(args[2], 10, 64)
+		if err != nil || threshold <= 0 {
+			return h.replyText(msg.Chat.ID, usage)
+		}
+	}
+
+	level, err := h.inventory.SetBottles(msg.Chat.ID, sizeML, count, threshold, add, time.Now())
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to save bottle stock."))
+		return fmt.Errorf("set bottles: %w", err)
+	}
+	return h.replyText(msg.Chat.ID, inventory.FormatBottles([]inventory.BottleLevel{level}, p))
+}
+
+func (h *Handler) handleCount(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	usage := p.Sprintf("Usage: /count <size> <counted>, e.g. /count 0.5 96")
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) != 2 {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+	sizeML, err := parseLiters(args[0])
+	if err != nil {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+	counted, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || counted < 0 {
+		return h.replyText(msg.Chat.ID, usage)
+	}
+
+	result, err := h.inventory.CountBottles(msg.Chat.ID, sizeML, counted, time.Now())
+	if errors.Is(err, inventory.ErrNoStock) {
+		return h.replyText(msg.Chat.ID, p.Sprintf("No stock recorded for %s bottles. Use /stock first.", args[0]))
+	}
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to save the count."))
+		return fmt.Errorf("count bottles: %w", err)
+	}
+	return h.replyText(msg.Chat.ID, result.Format(p))
+}
+
+func parseLiters(raw string) (int64, error) {
+	liters, err := strconv.ParseFloat(strings.Replace(strings.TrimSuffix(strings.ToLower(raw), "l"), ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	if liters <= 0 {
+		return 0, fmt.Errorf("liters must be positive")
+	}
+	return int64(liters*1000 + 0.5), nil
+}
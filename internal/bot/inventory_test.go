@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitKegSize(t *testing.T) {
+	cases := []struct {
+		args    string
+		product string
+		sizeML  int64
+	}{
+		{"Kozel 11", "Kozel 11", 0},
+		{"Kozel 30l", "Kozel", 30_000},
+		{"Pilsner Urquell 50L", "Pilsner Urquell", 50_000},
+		{"IPA 15,5l", "IPA", 15_500},
+		{"Pale Ale", "Pale Ale", 0},
+	}
+	for _, tc := range cases {
+		args, sizeML := splitKegSize(strings.Fields(tc.args))
+		if !slices.Equal(args, strings.Fields(tc.product)) || sizeML != tc.sizeML {
+			t.Fatalf("%q: expected %q and %d, got %q and %d", tc.args, tc.product, tc.sizeML, args, sizeML)
+		}
+	}
+}
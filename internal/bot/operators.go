@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/history"
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+)
+
+func (h *Handler) handleOperators(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	minReceipts := h.operatorMinReceipts
+	if raw := strings.TrimSpace(msg.CommandArguments()); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return h.replyText(msg.Chat.ID, p.Sprintf("Usage: /operators [minimum receipts]"))
+		}
+		minReceipts = n
+	}
+
+	records, err := h.loadHistory(msg.Chat.ID)
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load report history."))
+		return err
+	}
+
+	stats, reports := history.Operators(records)
+	if len(stats) == 0 {
+		return h.replyText(msg.Chat.ID, p.Sprintf("No operator data yet. The export needs an operator column (e.g. Pokladník)."))
+	}
+
+	ranked, unranked := processor.RankOperators(stats, minReceipts)
+	return h.replyText(msg.Chat.ID, formatOperatorRanking(ranked, unranked, minReceipts, reports, p))
+}
+
+func formatOperatorRanking(ranked, unranked []processor.OperatorStat, minReceipts, reports int, p i18n.Printer) string {
+	var b strings.Builder
+	b.WriteString(p.Plural(reports, "Mismatch rate per operator over %d report:\n", "Mismatch rate per operator over %d reports:\n", reports))
+	if len(ranked) == 0 {
+		b.WriteString(p.Plural(minReceipts, "Nobody has %d receipt yet.\n", "Nobody has %d receipts yet.\n", minReceipts))
+	}
+	for i, s := range ranked {
+		b.WriteString(p.Sprintf("%d. %s: %.1f%% (%d of %d receipts)\n", i+1, s.Name, s.MismatchRate()*100, s.Mismatches, s.Receipts))
+	}
+	if len(unranked) > 0 {
+		names := make([]string, 0, len(unranked))
+		for _, s := range unranked {
+			names = append(names, fmt.Sprintf("%s (%d)", s.Name, s.Receipts))
+		}
+		b.WriteString(p.Plural(minReceipts, "Not ranked, fewer than %d receipt: %s\n", "Not ranked, fewer than %d receipts: %s\n", minReceipts, strings.Join(names, ", ")))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+)
+
+// Limits keeping the /receipt reply within Telegram's message limit, with
+// the same margin reports are rendered with.
+const (
+	maxReceiptReply   = 3900
+	maxReceiptReports = 10
+)
+
+func (h *Handler) handleReceipt(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	no := strings.TrimSpace(msg.CommandArguments())
+	if no == "" {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Usage: /receipt <number>"))
+	}
+
+	records, err := h.loadHistory(msg.Chat.ID)
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load report history."))
+		return err
+	}
+
+	var text string
+	var others []string
+	var checked []processor.ReceiptReport
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if text != "" {
+			if _, ok := rec.Report.FormatReceipt(no, p); ok {
+				others = append(others, fmt.Sprintf("#%d", rec.ID))
+			}
+			continue
+		}
+		body, ok := rec.Report.FormatReceipt(no, p)
+		if !ok {
+			continue
+		}
+		text = p.Sprintf("Report #%d (%s, %s)\n\n%s", rec.ID, rec.FileName, h.formatTime(msg.Chat.ID, rec.CreatedAt), body)
+		checked = rec.Report.CheckedReceipts(no)
+	}
+
+	if text == "" {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Receipt %s not found in stored reports.", no))
+	}
+	var tail string
+	if len(others) > maxReceiptReports {
+		others = append(others[:maxReceiptReports], p.Sprintf("and %d more", len(others)-maxReceiptReports))
+	}
+	if len(others) > 0 {
+		tail += "\n\n" + p.Sprintf("Also in reports %s", strings.Join(others, ", "))
+	}
+
+	resolved, err := h.resolutions.Resolved(msg.Chat.ID)
+	if err != nil {
+		return err
+	}
+	var mismatches []processor.ReceiptRef
+	for _, rr := range checked {
+		if res, ok := resolved.Lookup(rr); ok {
+			tail += "\n\n" + p.Sprintf("%s resolved by %s on %s", receiptLabel(rr.Ref()), res.User, h.formatTime(msg.Chat.ID, res.At))
+			if res.Comment != "" {
+				tail += ": " + res.Comment
+			}
+		}
+		if !rr.Match || rr.Flag != "" {
+			mismatches = append(mismatches, rr.Ref())
+		}
+	}
+	text = truncateReply(text, maxReceiptReply-len(tail), p) + tail
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	if len(mismatches) > 0 {
+		if keyboard, ok := resolveKeyboard(p, mismatches...); ok {
+			reply.ReplyMarkup = keyboard
+		}
+	}
+	_, err = h.api.Send(reply)
+	return err
+}
+
+// truncateReply cuts text to at most limit bytes, at the end of a line when
+// there is one, and marks the cut in the language of p.
+func truncateReply(text string, limit int, p i18n.Printer) string {
+	marker := "\n" + p.Sprintf("...truncated")
+	if len(text) <= limit {
+		return text
+	}
+	cut := max(limit-len(marker), 0)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if i := strings.LastIndexByte(text[:cut], '\n'); i > 0 {
+		cut = i
+	}
+	return text[:cut] + marker
+}
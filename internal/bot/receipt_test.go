@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestTruncateReply(t *testing.T) {
+	en := i18n.For(i18n.English)
+	if got := truncateReply("short", 100, en); got != "short" {
+		t.Fatalf("expected short text unchanged, got %q", got)
+	}
+
+	text := strings.Repeat("Řádek účtenky\n", 400)
+	got := truncateReply(text, 3900, en)
+	if len(got) > 3900 || !strings.HasSuffix(got, "\n...truncated") {
+		t.Fatalf("expected at most 3900 bytes with a marker, got %d bytes", len(got))
+	}
+	if !strings.HasSuffix(strings.TrimSuffix(got, "\n...truncated"), "účtenky") {
+		t.Fatalf("expected a cut at the end of a line, got %q", got[len(got)-40:])
+	}
+
+	long := strings.Repeat("ž", 100)
+	if got := truncateReply(long, 50, en); len(got) > 50 || !utf8.ValidString(got) {
+		t.Fatalf("expected a cut between runes, got %q", got)
+	}
+}
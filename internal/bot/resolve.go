@@ -0,0 +1,282 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/history"
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+)
+
+const (
+	resolveCallbackPrefix = "resolve:"
+	// Telegram limits callback data to 64 bytes.
+	maxCallbackData = 64
+	logTail         = 20
+)
+
+// pendingComments remembers which receipt a "reply to add a comment" prompt
+// belongs to. It is kept in memory; after a restart a reply to an old prompt
+// is simply ignored.
+type pendingComments struct {
+	mu        sync.Mutex
+	byMessage map[pendingKey]processor.ReceiptRef
+}
+
+type pendingKey struct {
+	chatID    int64
+	messageID int
+}
+
+func newPendingComments() *pendingComments {
+	return &pendingComments{byMessage: make(map[pendingKey]processor.ReceiptRef)}
+}
+
+func (p *pendingComments) Set(chatID int64, messageID int, ref processor.ReceiptRef) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.byMessage[pendingKey{chatID, messageID}] = ref
+}
+
+func (p *pendingComments) Take(chatID int64, messageID int) (processor.ReceiptRef, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := pendingKey{chatID, messageID}
+	ref, ok := p.byMessage[key]
+	delete(p.byMessage, key)
+	return ref, ok
+}
+
+// resolveKeyboard offers the resolution reasons for receipts, a row each.
+// The callback data is resolve:<reason>:<date>:<register>:<number>. It
+// returns false when a receipt does not fit into the callback data.
+func resolveKeyboard(p i18n.Printer, refs ...processor.ReceiptRef) (tgbotapi.InlineKeyboardMarkup, bool) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, ref := range refs {
+		if strings.Contains(ref.Date+ref.Register, ":") {
+			return tgbotapi.InlineKeyboardMarkup{}, false
+		}
+		var row []tgbotapi.InlineKeyboardButton
+		for _, reason := range processor.ResolutionReasons {
+			data := resolveCallbackPrefix + reason + ":" + ref.Date + ":" + ref.Register + ":" + ref.No
+			if len(data) > maxCallbackData {
+				return tgbotapi.InlineKeyboardMarkup{}, false
+			}
+			title := processor.ResolutionTitle(reason, p)
+			if len(refs) > 1 {
+				title = receiptLabel(ref) + ": " + title
+			}
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(title, data))
+		}
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+// parseResolveCallback reads the reason and receipt of resolveKeyboard data.
+func parseResolveCallback(data string) (string, processor.ReceiptRef, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(data, resolveCallbackPrefix), ":", 4)
+	if len(parts) != 4 || parts[3] == "" || !slices.Contains(processor.ResolutionReasons, parts[0]) {
+		return "", processor.ReceiptRef{}, false
+	}
+	return parts[0], processor.ReceiptRef{Date: parts[1], Register: parts[2], No: parts[3]}, true
+}
+
+// receiptLabel names a receipt in replies, with its register when known.
+func receiptLabel(ref processor.ReceiptRef) string {
+	if ref.Register == "" {
+		return ref.No
+	}
+	return ref.No + " (" + ref.Register + ")"
+}
+
+func (h *Handler) handleResolve(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	no := strings.TrimSpace(msg.CommandArguments())
+	if no == "" {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Usage: /resolve <receipt number>"))
+	}
+
+	records, err := h.loadHistory(msg.Chat.ID)
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load report history."))
+		return err
+	}
+	// Several registers can issue the same number; every receipt of the
+	// newest report that has it gets a prompt.
+	var found []processor.ReceiptReport
+	for i := len(records) - 1; i >= 0 && len(found) == 0; i-- {
+		found = records[i].Report.CheckedReceipts(no)
+	}
+	if len(found) == 0 {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Receipt %s not found in stored reports.", no))
+	}
+	prompted := false
+	for _, rr := range found {
+		if rr.Match && rr.Flag == "" {
+			continue
+		}
+		prompted = true
+		if err := h.sendResolvePrompt(msg.Chat.ID, rr.Ref(), rr.Resolution, p); err != nil {
+			return err
+		}
+	}
+	if !prompted {
+		return h.replyText(msg.Chat.ID, p.Sprintf("Receipt %s has no mismatch to resolve.", no))
+	}
+	return nil
+}
+
+func (h *Handler) sendResolvePrompt(chatID int64, ref processor.ReceiptRef, current string, p i18n.Printer) error {
+	keyboard, ok := resolveKeyboard(p, ref)
+	if !ok {
+		return h.replyText(chatID, p.Sprintf("Receipt %s is too long for buttons.", receiptLabel(ref)))
+	}
+	text := p.Sprintf("Resolve receipt %s as:", receiptLabel(ref))
+	if current != "" {
+		text = p.Sprintf("Receipt %s is resolved as %s. Change to:", receiptLabel(ref), processor.ResolutionTitle(current, p))
+	}
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ReplyMarkup = keyboard
+	_, err := h.api.Send(reply)
+	return err
+}
+
+func (h *Handler) handleResolveCallback(cb *tgbotapi.CallbackQuery) error {
+	chatID := cb.Message.Chat.ID
+	p := h.printer(chatID, cb.From)
+	reason, ref, ok := parseResolveCallback(cb.Data)
+	if !ok {
+		_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, p.Sprintf("Unknown action.")))
+		return nil
+	}
+
+	err := h.resolutions.Append(chatID, history.Action{
+		At:        time.Now(),
+		Kind:      history.ActionResolve,
+		UserID:    cb.From.ID,
+		User:      userLabel(cb.From),
+		ReceiptNo: ref.No,
+		Register:  ref.Register,
+		Date:      ref.Date,
+		Reason:    reason,
+	})
+	if err != nil {
+		_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, p.Sprintf("Failed to save.")))
+		return fmt.Errorf("resolve receipt %s: %w", ref.No, err)
+	}
+	_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, p.Sprintf("Saved.")))
+
+	text := p.Sprintf("Receipt %s resolved as %s by %s.", receiptLabel(ref), processor.ResolutionTitle(reason, p), userLabel(cb.From))
+	_, _ = h.api.Send(tgbotapi.NewEditMessageText(chatID, cb.Message.MessageID, text))
+
+	prompt := tgbotapi.NewMessage(chatID, p.Sprintf("Reply to this message to add a comment to receipt %s.", receiptLabel(ref)))
+	prompt.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
+	sent, err := h.api.Send(prompt)
+	if err != nil {
+		return err
+	}
+	h.comments.Set(chatID, sent.MessageID, ref)
+	return nil
+}
+
+// handleComment stores a reply to a comment prompt. It reports whether msg
+// was such a reply.
+func (h *Handler) handleComment(msg *tgbotapi.Message) (bool, error) {
+	if msg.ReplyToMessage == nil || strings.TrimSpace(msg.Text) == "" {
+		return false, nil
+	}
+	ref, ok := h.comments.Take(msg.Chat.ID, msg.ReplyToMessage.MessageID)
+	if !ok {
+		return false, nil
+	}
+
+	p := h.printer(msg.Chat.ID, msg.From)
+	err := h.resolutions.Append(msg.Chat.ID, history.Action{
+		At:        time.Now(),
+		Kind:      history.ActionComment,
+		UserID:    msg.From.ID,
+		User:      userLabel(msg.From),
+		ReceiptNo: ref.No,
+		Register:  ref.Register,
+		Date:      ref.Date,
+		Comment:   strings.TrimSpace(msg.Text),
+	})
+	if errors.Is(err, history.ErrNotFound) {
+		return true, h.replyText(msg.Chat.ID, p.Sprintf("Receipt %s is not resolved.", receiptLabel(ref)))
+	}
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to save the comment."))
+		return true, fmt.Errorf("comment receipt %s: %w", ref.No, err)
+	}
+	return true, h.replyText(msg.Chat.ID, p.Sprintf("Comment added to receipt %s.", receiptLabel(ref)))
+}
+
+func (h *Handler) handleResolutions(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	actions, err := h.resolutions.Log(msg.Chat.ID)
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load the resolution log."))
+		return err
+	}
+	if len(actions) == 0 {
+		return h.replyText(msg.Chat.ID, p.Sprintf("No mismatches resolved yet. Use /resolve <receipt number>."))
+	}
+	if len(actions) > logTail {
+		actions = actions[len(actions)-logTail:]
+	}
+
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Resolution log:\n"))
+	for _, a := range actions {
+		b.WriteString(h.formatTime(msg.Chat.ID, a.At) + " " + a.User + ": ")
+		switch a.Kind {
+		case history.ActionComment:
+			b.WriteString(p.Sprintf("comment on %s: %s\n", receiptLabel(a.Ref()), a.Comment))
+		default:
+			b.WriteString(p.Sprintf("%s resolved as %s\n", receiptLabel(a.Ref()), processor.ResolutionTitle(a.Reason, p)))
+		}
+	}
+	return h.replyText(msg.Chat.ID, strings.TrimSpace(b.String()))
+}
+
+// loadHistory returns the stored reports of the chat with the current
+// resolutions applied.
+func (h *Handler) loadHistory(chatID int64) ([]history.Record, error) {
+	records, err := h.history.List(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("list history: %w", err)
+	}
+	if err := h.applyResolutions(chatID, records...); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (h *Handler) applyResolutions(chatID int64, records ...history.Record) error {
+	resolved, err := h.resolutions.Resolved(chatID)
+	if err != nil {
+		return fmt.Errorf("load resolutions: %w", err)
+	}
+	for i := range records {
+		records[i].Report.ApplyResolutions(resolved)
+	}
+	return nil
+}
+
+func userLabel(u *tgbotapi.User) string {
+	if u == nil {
+		return "unknown"
+	}
+	if u.UserName != "" {
+		return "@" + u.UserName
+	}
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/history"
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+	"bigbrother/internal/settings"
+)
+
+// apiCall is a request the bot made to the fake Telegram API.
+type apiCall struct {
+	method string
+	params url.Values
+}
+
+// newTestAPI returns a bot talking to a fake Telegram API that accepts
+// every request, and the requests it received.
+func newTestAPI(t *testing.T) (*tgbotapi.BotAPI, func() []apiCall) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []apiCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		mu.Lock()
+		calls = append(calls, apiCall{method: method, params: r.PostForm})
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch method {
+		case "getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"bot","username":"bot"}}`))
+		case "answerCallbackQuery":
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":7,"chat":{"id":1}}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient("token", srv.URL+"/bot%s/%s", srv.Client())
+	if err != nil {
+		t.Fatalf("new bot api: %v", err)
+	}
+	return api, func() []apiCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]apiCall(nil), calls...)
+	}
+}
+
+func TestHandleResolveCallback(t *testing.T) {
+	api, calls := newTestAPI(t)
+	h := &Handler{
+		api:         api,
+		resolutions: history.NewResolutionStore(t.TempDir()),
+		comments:    newPendingComments(),
+		settings:    settings.NewStore(t.TempDir(), settings.Settings{}),
+	}
+
+	ref := processor.ReceiptRef{Register: "Pokladna 1", No: "R1", Date: "2026-02-06"}
+	keyboard, ok := resolveKeyboard(i18n.For(i18n.English), ref)
+	if !ok || len(keyboard.InlineKeyboard[0]) != len(processor.ResolutionReasons) {
+		t.Fatalf("expected a button per reason, got %+v", keyboard)
+	}
+	for _, button := range keyboard.InlineKeyboard[0] {
+		cb := &tgbotapi.CallbackQuery{
+			ID:      "cb",
+			From:    &tgbotapi.User{ID: 5, UserName: "jana"},
+			Message: &tgbotapi.Message{MessageID: 3, Chat: &tgbotapi.Chat{ID: 1}},
+			Data:    *button.CallbackData,
+		}
+		if err := h.handleResolveCallback(cb); err != nil {
+			t.Fatalf("%s: %v", button.Text, err)
+		}
+		answer := calls()[len(calls())-3]
+		if answer.method != "answerCallbackQuery" || answer.params.Get("text") != "Saved." {
+			t.Fatalf("%s: expected the resolution saved, got %+v", button.Text, answer)
+		}
+	}
+
+	actions, err := h.resolutions.Log(1)
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if len(actions) != len(processor.ResolutionReasons) {
+		t.Fatalf("expected an action per button, got %+v", actions)
+	}
+	for i, reason := range processor.ResolutionReasons {
+		if actions[i].Reason != reason || actions[i].Ref() != ref {
+			t.Fatalf("expected %+v resolved as %s, got %+v", ref, reason, actions[i])
+		}
+	}
+
+	cb := &tgbotapi.CallbackQuery{ID: "cb", Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 1}}, Data: resolveCallbackPrefix + "lost:2026-02-06::R1"}
+	if err := h.handleResolveCallback(cb); err != nil {
+		t.Fatalf("unknown reason: %v", err)
+	}
+	if last := calls()[len(calls())-1]; last.params.Get("text") != "Unknown action." {
+		t.Fatalf("expected an unknown reason rejected, got %+v", last)
+	}
+}
+
+func TestResolveKeyboard(t *testing.T) {
+	refs := []processor.ReceiptRef{
+		{Register: "Pokladna 1", No: "100", Date: "2026-02-06"},
+		{Register: "Pokladna 2", No: "100", Date: "2026-02-06"},
+	}
+	keyboard, ok := resolveKeyboard(i18n.For(i18n.English), refs...)
+	if !ok || len(keyboard.InlineKeyboard) != 2 {
+		t.Fatalf("expected a row per receipt, got %+v", keyboard)
+	}
+	button := keyboard.InlineKeyboard[1][0]
+	if button.Text != "100 (Pokladna 2): explained" {
+		t.Fatalf("expected the register in the button, got %q", button.Text)
+	}
+	reason, ref, ok := parseResolveCallback(*button.CallbackData)
+	if !ok || reason != processor.ResolutionExplained || ref != refs[1] {
+		t.Fatalf("expected the second receipt back, got %s %+v", reason, ref)
+	}
+
+	if _, ok := resolveKeyboard(i18n.For(i18n.English), processor.ReceiptRef{Register: "A:1", No: "100"}); ok {
+		t.Fatal("expected a register with a colon to be refused")
+	}
+	if _, ok := resolveKeyboard(i18n.For(i18n.English), processor.ReceiptRef{No: strings.Repeat("9", 50)}); ok {
+		t.Fatal("expected a long number to be refused")
+	}
+}
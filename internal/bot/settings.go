@@ -0,0 +1,395 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+	"bigbrother/internal/settings"
+)
+
+const (
+	settingsCallbackPrefix = "set:"
+	settingsUsage          = `Usage:
+/settings - open the settings menu
+/settings tolerance <ml>|default
+/settings snark off|mismatch|always|default
+/settings tone <name>|default
+/settings timezone <Area/City>|default
+/settings columns <profile>|default
+/settings language en|cs|uk|default
+/settings audit <name> - toggle an audit
+/settings reset`
+)
+
+// Choices offered as buttons. Other tolerances and time zones can be set
+// with /settings.
+var (
+	toleranceChoices = []int64{0, 20, 50, 100, 250}
+	timezoneChoices  = []string{"Europe/Prague", "Europe/Kyiv", "Europe/London", "UTC"}
+)
+
+var errInvalidSetting = errors.New("invalid setting")
+
+// settingError is an errInvalidSetting with a message for the chat.
+type settingError struct {
+	msg string
+}
+
+func (e settingError) Error() string        { return e.msg }
+func (e settingError) Is(target error) bool { return target == errInvalidSetting }
+
+// chatSettings returns the settings in effect for a chat. When they cannot
+// be read, the global defaults apply.
+func (h *Handler) chatSettings(chatID int64) settings.Settings {
+	s, _ := h.settings.Get(chatID)
+	return s
+}
+
+// language returns the language of a chat: its own setting, else the
+// language of the Telegram client behind code, else English.
+func (h *Handler) language(chatID int64, code string) string {
+	if lang := h.chatSettings(chatID).Language; lang != "" {
+		return lang
+	}
+	if lang := i18n.Match(code); lang != "" {
+		return lang
+	}
+	return i18n.English
+}
+
+// printer returns the printer for replies to from in a chat.
+func (h *Handler) printer(chatID int64, from *tgbotapi.User) i18n.Printer {
+	code := ""
+	if from != nil {
+		code = from.LanguageCode
+	}
+	return i18n.For(h.language(chatID, code))
+}
+
+// formatTime formats t in the chat's time zone.
+func (h *Handler) formatTime(chatID int64, t time.Time) string {
+	return t.In(h.chatSettings(chatID).Location()).Format("02.01.2006 15:04")
+}
+
+// chatNow is the current time in the chat's time zone.
+func (h *Handler) chatNow(chatID int64) time.Time {
+	return time.Now().In(h.chatSettings(chatID).Location())
+}
+
+// columnProfile returns the named profile, or the default one when it is
+// no longer configured.
+func (h *Handler) columnProfile(name string) processor.ColumnProfile {
+	for _, p := range h.profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return processor.DefaultProfile
+}
+
+func (h *Handler) handleSettings(msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+	p := h.printer(chatID, msg.From)
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		text, keyboard := h.settingsMenu(chatID, p)
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ReplyMarkup = keyboard
+		_, err := h.api.Send(reply)
+		return err
+	}
+
+	if len(args) == 1 && args[0] == "reset" {
+		if err := h.settings.Reset(chatID); err != nil {
+			_ = h.replyText(chatID, p.Sprintf("Failed to save the settings."))
+			return err
+		}
+		return h.replyText(chatID, h.formatSettings(chatID, h.printer(chatID, msg.From)))
+	}
+	if len(args) != 2 {
+		return h.replyText(chatID, p.Text(settingsUsage))
+	}
+
+	err := h.setSetting(chatID, args[0], args[1], p)
+	if errors.Is(err, errInvalidSetting) {
+		return h.replyText(chatID, err.Error()+"\n"+p.Text(settingsUsage))
+	}
+	if err != nil {
+		_ = h.replyText(chatID, p.Sprintf("Failed to save the settings."))
+		return err
+	}
+	// A new language applies to the reply right away.
+	return h.replyText(chatID, h.formatSettings(chatID, h.printer(chatID, msg.From)))
+}
+
+// setSetting validates and stores one setting. "default" removes the
+// chat's own value. Validation errors are written with p.
+func (h *Handler) setSetting(chatID int64, name, value string, p i18n.Printer) error {
+	reset := strings.EqualFold(value, "default")
+	invalid := func(format string, args ...any) error {
+		return settingError{msg: p.Sprintf(format, args...)}
+	}
+	_, err := h.settings.Update(chatID, func(o *settings.Overrides, current settings.Settings) error {
+		switch name {
+		case "tolerance":
+			if reset {
+				o.ToleranceML = nil
+				return nil
+			}
+			ml, err := strconv.ParseInt(strings.TrimSuffix(strings.ToLower(value), "ml"), 10, 64)
+			if err != nil || ml < 0 {
+				return invalid("Tolerance must be a number of milliliters.")
+			}
+			o.ToleranceML = &ml
+		case "snark":
+			if reset {
+				o.Snark = nil
+				return nil
+			}
+			level := strings.ToLower(value)
+			if !slices.Contains(settings.SnarkLevels, level) {
+				return invalid("Unknown snark level %s.", value)
+			}
+			o.Snark = &level
+		case "tone":
+			if reset {
+				o.Tone = nil
+				return nil
+			}
+			tone := strings.ToLower(value)
+			if !h.phrases.HasTone(tone) {
+				return invalid("Unknown tone %s.", value)
+			}
+			o.Tone = &tone
+		case "timezone":
+			if reset {
+				o.Timezone = nil
+				return nil
+			}
+			loc, err := time.LoadLocation(value)
+			if err != nil {
+				return invalid("Unknown time zone %s.", value)
+			}
+			zone := loc.String()
+			o.Timezone = &zone
+		case "columns":
+			if reset {
+				o.Profile = nil
+				return nil
+			}
+			profile := strings.ToLower(value)
+			if h.columnProfile(profile).Name != profile {
+				return invalid("Unknown column profile %s.", value)
+			}
+			o.Profile = &profile
+		case "audit":
+			audit, ok := h.registry.Lookup(value)
+			if !ok {
+				return invalid("Unknown audit %s.", value)
+			}
+			audits := toggleAudit(current.Audits, audit.Name(), !slices.Contains(current.Audits, audit.Name()))
+			o.Audits = &audits
+		case "language":
+			if reset {
+				o.Language = nil
+				return nil
+			}
+			lang := i18n.Match(value)
+			if lang == "" {
+				return invalid("Unknown language %s.", value)
+			}
+			o.Language = &lang
+		default:
+			return invalid("Unknown setting %s.", name)
+		}
+		return nil
+	})
+	return err
+}
+
+// toggleAudit returns names with name added or removed.
+func toggleAudit(names []string, name string, enabled bool) []string {
+	next := make([]string, 0, len(names)+1)
+	for _, existing := range names {
+		if existing != name {
+			next = append(next, existing)
+		}
+	}
+	if enabled {
+		next = append(next, name)
+	}
+	return next
+}
+
+func (h *Handler) formatSettings(chatID int64, p i18n.Printer) string {
+	s := h.chatSettings(chatID)
+	o, _ := h.settings.Overrides(chatID)
+	mark := func(set bool) string {
+		if set {
+			return ""
+		}
+		return p.Sprintf(" (default)")
+	}
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = p.Sprintf("server time")
+	}
+	language := p.Sprintf("from Telegram")
+	if s.Language != "" {
+		language = i18n.Name(s.Language)
+	}
+
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Settings for this chat:\n"))
+	b.WriteString(p.Sprintf("Tolerance: %d ml%s\n", s.ToleranceML, mark(o.ToleranceML != nil)))
+	b.WriteString(p.Sprintf("Snark: %s%s\n", s.Snark, mark(o.Snark != nil)))
+	b.WriteString(p.Sprintf("Tone: %s%s\n", s.Tone, mark(o.Tone != nil)))
+	b.WriteString(p.Sprintf("Time zone: %s%s\n", timezone, mark(o.Timezone != nil)))
+	b.WriteString(p.Sprintf("Audits: %s%s\n", strings.Join(s.Audits, ", "), mark(o.Audits != nil)))
+	b.WriteString(p.Sprintf("Columns: %s%s\n", h.columnProfile(s.Profile).Name, mark(o.Profile != nil)))
+	b.WriteString(p.Sprintf("Language: %s%s", language, mark(o.Language != nil)))
+	return b.String()
+}
+
+func (h *Handler) settingsMenu(chatID int64, p i18n.Printer) (string, tgbotapi.InlineKeyboardMarkup) {
+	return h.formatSettings(chatID, p), tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Tolerance"), settingsCallbackPrefix+"tolerance"),
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Snark"), settingsCallbackPrefix+"snark"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Time zone"), settingsCallbackPrefix+"timezone"),
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Audits"), settingsCallbackPrefix+"audit"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Columns"), settingsCallbackPrefix+"columns"),
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Language"), settingsCallbackPrefix+"language"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Tone"), settingsCallbackPrefix+"tone"),
+			tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Reset"), settingsCallbackPrefix+"reset"),
+		),
+	)
+}
+
+// settingsSubmenu lists the choices for one setting. Buttons carry
+// "set:<name>:<value>".
+func (h *Handler) settingsSubmenu(chatID int64, name string, p i18n.Printer) (string, tgbotapi.InlineKeyboardMarkup, bool) {
+	s := h.chatSettings(chatID)
+	var values, labels []string
+	title := ""
+	switch name {
+	case "tolerance":
+		title = p.Sprintf("Beer vs bottles difference still counted as a match:")
+		for _, ml := range toleranceChoices {
+			values = append(values, strconv.FormatInt(ml, 10))
+			labels = append(labels, choiceLabel(fmt.Sprintf("%d ml", ml), ml == s.ToleranceML))
+		}
+	case "snark":
+		title = p.Sprintf("Snarky remarks after a report:")
+		for _, level := range settings.SnarkLevels {
+			values = append(values, level)
+			labels = append(labels, choiceLabel(level, level == s.Snark))
+		}
+	case "tone":
+		title = p.Sprintf("Tone of the phrases in reports and remarks:")
+		for _, tone := range h.phrases.Tones() {
+			values = append(values, tone)
+			labels = append(labels, choiceLabel(tone, tone == s.Tone))
+		}
+	case "timezone":
+		title = p.Sprintf("Time zone for dates and day ranges (other zones: /settings timezone <Area/City>):")
+		for _, zone := range timezoneChoices {
+			values = append(values, zone)
+			labels = append(labels, choiceLabel(zone, zone == s.Timezone))
+		}
+	case "audit":
+		title = p.Sprintf("Audits run on uploads:")
+		for _, audit := range h.registry.Audits() {
+			values = append(values, audit.Name())
+			labels = append(labels, choiceLabel(p.Text(audit.Title()), slices.Contains(s.Audits, audit.Name())))
+		}
+	case "columns":
+		title = p.Sprintf("Column names of the export:")
+		for _, profile := range h.profiles {
+			values = append(values, profile.Name)
+			labels = append(labels, choiceLabel(profile.Name, profile.Name == h.columnProfile(s.Profile).Name))
+		}
+	case "language":
+		title = p.Sprintf("Language of replies and reports (default: the language of your Telegram app):")
+		for _, lang := range i18n.Languages {
+			values = append(values, lang)
+			labels = append(labels, choiceLabel(i18n.Name(lang), lang == s.Language))
+		}
+	default:
+		return "", tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, value := range values {
+		data := settingsCallbackPrefix + name + ":" + value
+		if len(data) > maxCallbackData {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(labels[i], data)))
+	}
+	last := []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("« Back"), settingsCallbackPrefix+"menu")}
+	if name != "audit" {
+		last = append(last, tgbotapi.NewInlineKeyboardButtonData(p.Sprintf("Default"), settingsCallbackPrefix+name+":default"))
+	}
+	rows = append(rows, last)
+	return title, tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+func choiceLabel(label string, selected bool) string {
+	if selected {
+		return "✅ " + label
+	}
+	return label
+}
+
+func (h *Handler) handleSettingsCallback(cb *tgbotapi.CallbackQuery) error {
+	chatID := cb.Message.Chat.ID
+	name, value, hasValue := strings.Cut(strings.TrimPrefix(cb.Data, settingsCallbackPrefix), ":")
+	p := h.printer(chatID, cb.From)
+
+	switch {
+	case name == "reset":
+		if err := h.settings.Reset(chatID); err != nil {
+			_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, p.Sprintf("Failed to save.")))
+			return err
+		}
+	case hasValue:
+		err := h.setSetting(chatID, name, value, p)
+		if errors.Is(err, errInvalidSetting) {
+			_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, p.Sprintf("Unknown option.")))
+			return nil
+		}
+		if err != nil {
+			_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, p.Sprintf("Failed to save.")))
+			return err
+		}
+	}
+	_, _ = h.api.Request(tgbotapi.NewCallback(cb.ID, ""))
+	p = h.printer(chatID, cb.From)
+
+	// Audits stay open so several can be toggled; everything else returns
+	// to the main menu.
+	if name != "menu" && name != "reset" && (!hasValue || name == "audit") {
+		if title, keyboard, ok := h.settingsSubmenu(chatID, name, p); ok {
+			_, err := h.api.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, cb.Message.MessageID, title, keyboard))
+			return err
+		}
+	}
+	text, keyboard := h.settingsMenu(chatID, p)
+	_, err := h.api.Send(tgbotapi.NewEditMessageTextAndMarkup(chatID, cb.Message.MessageID, text, keyboard))
+	return err
+}
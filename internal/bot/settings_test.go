@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+	"bigbrother/internal/settings"
+)
+
+func TestSetSetting(t *testing.T) {
+	h := &Handler{
+		registry: processor.NewRegistry(processor.NewBottlesAudit(), processor.NewSequenceAudit()),
+		profiles: []processor.ColumnProfile{processor.DefaultProfile, {Name: "other"}},
+		phrases:  processor.NewPhrasebook(rand.NewSource(1)),
+		settings: settings.NewStore(t.TempDir(), settings.Settings{
+			Snark:   settings.SnarkMismatch,
+			Audits:  []string{processor.AuditBottles, processor.AuditSequence},
+			Profile: processor.DefaultProfile.Name,
+		}),
+	}
+
+	for _, set := range [][2]string{
+		{"tolerance", "50ml"},
+		{"snark", "Always"},
+		{"tone", "Neutral"},
+		{"timezone", "Europe/Prague"},
+		{"columns", "other"},
+		{"audit", processor.AuditSequence},
+		{"language", "uk-UA"},
+	} {
+		if err := h.setSetting(1, set[0], set[1], i18n.For(i18n.English)); err != nil {
+			t.Fatalf("set %s: %v", set[0], err)
+		}
+	}
+	s := h.chatSettings(1)
+	if s.ToleranceML != 50 || s.Snark != settings.SnarkAlways || s.Tone != processor.ToneNeutral || s.Timezone != "Europe/Prague" || s.Profile != "other" || s.Language != i18n.Ukrainian {
+		t.Fatalf("unexpected settings: %+v", s)
+	}
+	if len(s.Audits) != 1 || s.Audits[0] != processor.AuditBottles {
+		t.Fatalf("expected the sequence audit toggled off, got: %v", s.Audits)
+	}
+
+	if err := h.setSetting(1, "snark", "default", i18n.For(i18n.English)); err != nil || h.chatSettings(1).Snark != settings.SnarkMismatch {
+		t.Fatalf("expected snark back at the default, got %q (%v)", h.chatSettings(1).Snark, err)
+	}
+	for _, set := range [][2]string{
+		{"tolerance", "-5"},
+		{"snark", "loud"},
+		{"tone", "rude"},
+		{"timezone", "Mars/Olympus"},
+		{"columns", "missing"},
+		{"audit", "nope"},
+		{"language", "klingon"},
+		{"colour", "red"},
+	} {
+		if err := h.setSetting(1, set[0], set[1], i18n.For(i18n.English)); !errors.Is(err, errInvalidSetting) {
+			t.Fatalf("%s=%s: expected invalid setting, got: %v", set[0], set[1], err)
+		}
+	}
+}
+
+// The help and usage texts are catalog keys, so editing them without the
+// catalogs would silently fall back to English.
+func TestHelpTranslated(t *testing.T) {
+	for _, lang := range []string{i18n.Czech, i18n.Ukrainian} {
+		p := i18n.For(lang)
+		if p.Sprintf(helpText) == helpText || p.Sprintf(settingsUsage) == settingsUsage || p.Sprintf(snarkUsage) == snarkUsage {
+			t.Fatalf("%s: help or usage is not translated", lang)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/config"
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+	"bigbrother/internal/settings"
+)
+
+const snarkUsage = `Usage:
+/snark - list the phrases of this chat
+/snark add <kind> <text> - add a phrase
+/snark remove <kind> <number> - remove a phrase
+Kinds: match (opens a clean report), mismatch and snark-mismatch (remark after mismatches), snark-match (remark after a clean report)`
+
+// Limits on the phrases a chat can add.
+const (
+	maxChatPhrases = 50
+	maxPhraseRunes = 200
+)
+
+var (
+	errTooManyPhrases = errors.New("too many phrases")
+	errNoSuchPhrase   = errors.New("no such phrase")
+)
+
+func buildPhrasebook(cfg config.Config) (*processor.Phrasebook, error) {
+	book := processor.NewPhrasebook(rand.NewSource(time.Now().UnixNano()))
+	if cfg.PhrasesDir != "" {
+		packs, err := processor.LoadPhrasePacks(cfg.PhrasesDir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PHRASES_DIR: %w", err)
+		}
+		for _, pack := range packs {
+			if err := book.AddPack(pack); err != nil {
+				return nil, fmt.Errorf("invalid PHRASES_DIR: %w", err)
+			}
+		}
+	}
+	if !book.HasTone(cfg.Tone) {
+		return nil, fmt.Errorf("invalid TONE: %s", cfg.Tone)
+	}
+	return book, nil
+}
+
+// remark returns the remark to send after a report, following the chat's
+// snark level and tone.
+func (h *Handler) remark(report processor.Report, s settings.Settings) string {
+	switch s.Snark {
+	case settings.SnarkOff:
+		return ""
+	case settings.SnarkAlways:
+		if remark := h.phrases.MismatchRemark(report, s.Tone, s.Phrases); remark != "" {
+			return remark
+		}
+		return h.phrases.MatchRemark(report, s.Tone, s.Phrases)
+	default:
+		return h.phrases.MismatchRemark(report, s.Tone, s.Phrases)
+	}
+}
+
+func (h *Handler) handleSnark(msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+	p := h.printer(chatID, msg.From)
+	action, rest, _ := strings.Cut(strings.TrimSpace(msg.CommandArguments()), " ")
+	kind, arg, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	kind = strings.ToLower(kind)
+	arg = strings.TrimSpace(arg)
+
+	switch {
+	case action == "":
+		return h.replyText(chatID, h.formatPhrases(chatID, p))
+	case (action == "add" || action == "remove") && !slices.Contains(processor.PhraseKinds, kind):
+		return h.replyText(chatID, p.Sprintf("Unknown phrase kind %s.", kind)+"\n"+p.Text(snarkUsage))
+	case action == "add" && arg != "":
+		if utf8.RuneCountInString(arg) > maxPhraseRunes {
+			return h.replyText(chatID, p.Sprintf("Phrases can be at most %d characters long.", maxPhraseRunes))
+		}
+		err := h.updatePhrases(chatID, func(phrases map[string][]string) error {
+			total := 0
+			for _, list := range phrases {
+				total += len(list)
+			}
+			if total >= maxChatPhrases {
+				return errTooManyPhrases
+			}
+			phrases[kind] = append(phrases[kind], arg)
+			return nil
+		})
+		if errors.Is(err, errTooManyPhrases) {
+			return h.replyText(chatID, p.Sprintf("A chat can have at most %d phrases. Remove some first.", maxChatPhrases))
+		}
+		if err != nil {
+			_ = h.replyText(chatID, p.Sprintf("Failed to save the settings."))
+			return err
+		}
+		return h.replyText(chatID, h.formatPhrases(chatID, p))
+	case action == "remove":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			return h.replyText(chatID, p.Text(snarkUsage))
+		}
+		err = h.updatePhrases(chatID, func(phrases map[string][]string) error {
+			if n > len(phrases[kind]) {
+				return errNoSuchPhrase
+			}
+			phrases[kind] = slices.Delete(phrases[kind], n-1, n)
+			if len(phrases[kind]) == 0 {
+				delete(phrases, kind)
+			}
+			return nil
+		})
+		if errors.Is(err, errNoSuchPhrase) {
+			return h.replyText(chatID, p.Sprintf("There is no %s phrase number %d.", kind, n))
+		}
+		if err != nil {
+			_ = h.replyText(chatID, p.Sprintf("Failed to save the settings."))
+			return err
+		}
+		return h.replyText(chatID, h.formatPhrases(chatID, p))
+	default:
+		return h.replyText(chatID, p.Text(snarkUsage))
+	}
+}
+
+// updatePhrases applies fn to the chat's own phrases and saves them.
+func (h *Handler) updatePhrases(chatID int64, fn func(phrases map[string][]string) error) error {
+	_, err := h.settings.Update(chatID, func(o *settings.Overrides, _ settings.Settings) error {
+		if o.Phrases == nil {
+			o.Phrases = make(map[string][]string)
+		}
+		if err := fn(o.Phrases); err != nil {
+			return err
+		}
+		if len(o.Phrases) == 0 {
+			o.Phrases = nil
+		}
+		return nil
+	})
+	return err
+}
+
+func (h *Handler) formatPhrases(chatID int64, p i18n.Printer) string {
+	s := h.chatSettings(chatID)
+
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Tone: %s (change it with /settings tone)\n", s.Tone))
+	if len(s.Phrases) == 0 {
+		b.WriteString(p.Sprintf("This chat has no phrases of its own.") + "\n\n" + p.Text(snarkUsage))
+		return b.String()
+	}
+	for _, kind := range processor.PhraseKinds {
+		if len(s.Phrases[kind]) == 0 {
+			continue
+		}
+		b.WriteString("\n" + kind + ":\n")
+		for i, phrase := range s.Phrases[kind] {
+			b.WriteString(fmt.Sprintf("%d. %s\n", i+1, phrase))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
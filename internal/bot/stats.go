@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"bigbrother/internal/history"
+	"bigbrother/internal/i18n"
+)
+
+const maxStatsDays = 366
+
+func (h *Handler) handleStats(msg *tgbotapi.Message) error {
+	p := h.printer(msg.Chat.ID, msg.From)
+	from, to, err := parseStatsRange(msg.CommandArguments(), h.chatNow(msg.Chat.ID), p)
+	if err != nil {
+		return h.replyText(msg.Chat.ID, err.Error()+"\n"+p.Sprintf("Usage: /stats [7d|30d|2026-02-01..2026-02-28]"))
+	}
+
+	records, err := h.loadHistory(msg.Chat.ID)
+	if err != nil {
+		_ = h.replyText(msg.Chat.ID, p.Sprintf("Failed to load report history."))
+		return err
+	}
+	return h.replyText(msg.Chat.ID, history.FormatStats(history.Summarize(records, from, to), p))
+}
+
+// parseStatsRange turns "7d", "30d" or "from..to" into a day range with an
+// exclusive end. Receipt times carry no zone, so days are compared by the
+// wall clock of now. Errors are printed with p for the reply.
+func parseStatsRange(raw string, now time.Time, p i18n.Printer) (time.Time, time.Time, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if raw == "" {
+		raw = "7d"
+	}
+
+	if first, last, ok := strings.Cut(raw, ".."); ok {
+		from, err := time.Parse("2006-01-02", strings.TrimSpace(first))
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New(p.Sprintf("invalid date: %s", first))
+		}
+		to, err := time.Parse("2006-01-02", strings.TrimSpace(last))
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New(p.Sprintf("invalid date: %s", last))
+		}
+		to = to.AddDate(0, 0, 1)
+		if !from.Before(to) {
+			return time.Time{}, time.Time{}, errors.New(p.Sprintf("range ends before it starts"))
+		}
+		if to.Sub(from) > maxStatsDays*24*time.Hour {
+			return time.Time{}, time.Time{}, errors.New(p.Sprintf("range is longer than %d days", maxStatsDays))
+		}
+		return from, to, nil
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+	if err != nil || days <= 0 || days > maxStatsDays {
+		return time.Time{}, time.Time{}, errors.New(p.Sprintf("invalid range: %s", raw))
+	}
+	return today.AddDate(0, 0, 1-days), today.AddDate(0, 0, 1), nil
+}
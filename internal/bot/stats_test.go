@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestParseStatsRange(t *testing.T) {
+	now := time.Date(2026, 2, 10, 23, 30, 0, 0, time.Local)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	cases := []struct {
+		raw      string
+		from, to time.Time
+	}{
+		{"", day(2, 4), day(2, 11)},
+		{"30d", day(1, 12), day(2, 11)},
+		{"2026-02-01..2026-02-28", day(2, 1), day(3, 1)},
+	}
+	for _, tc := range cases {
+		from, to, err := parseStatsRange(tc.raw, now, i18n.For(i18n.English))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.raw, err)
+		}
+		if !from.Equal(tc.from) || !to.Equal(tc.to) {
+			t.Fatalf("%q: expected %s..%s, got %s..%s", tc.raw, tc.from, tc.to, from, to)
+		}
+	}
+
+	for _, raw := range []string{"0d", "week", "2026-02-10..2026-02-01", "2025-01-01..2026-12-31"} {
+		if _, _, err := parseStatsRange(raw, now, i18n.For(i18n.English)); err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package chart renders simple PNG charts without external dependencies.
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+)
+
+const (
+	width     = 800
+	height    = 400
+	padding   = 16
+	textScale = 2
+)
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	foreground = color.RGBA{40, 40, 40, 255}
+	gridColor  = color.RGBA{225, 225, 225, 255}
+	barColor   = color.RGBA{214, 134, 34, 255}
+	heatLow    = color.RGBA{250, 245, 235, 255}
+	heatHigh   = color.RGBA{200, 30, 30, 255}
+)
+
+// Bar is one column of a bar chart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// Bars renders a bar chart. Labels that would overlap are thinned out.
+func Bars(title string, bars []Bar) *image.RGBA {
+	img := newCanvas(width, height)
+	drawText(img, padding, padding, title, textScale, foreground)
+
+	highest := 0.0
+	for _, b := range bars {
+		highest = math.Max(highest, b.Value)
+	}
+	maxLabel := formatValue(highest)
+
+	left := padding + textWidth(maxLabel, textScale) + 8
+	top := padding + textHeight(textScale) + 16
+	bottom := height - padding - textHeight(textScale) - 8
+	right := width - padding
+	plotHeight := bottom - top
+
+	for _, frac := range []float64{0.25, 0.5, 0.75, 1} {
+		y := bottom - int(frac*float64(plotHeight))
+		fillRect(img, left, y, right-left, 1, gridColor)
+	}
+	drawText(img, padding, top, maxLabel, textScale, foreground)
+	drawText(img, padding, bottom-textHeight(textScale), "0", textScale, foreground)
+	fillRect(img, left, bottom, right-left, 1, foreground)
+
+	if len(bars) == 0 {
+		return img
+	}
+
+	slot := float64(right-left) / float64(len(bars))
+	labelWidth := 0
+	for _, b := range bars {
+		labelWidth = max(labelWidth, textWidth(b.Label, textScale))
+	}
+	every := max(1, int(math.Ceil(float64(labelWidth+8)/slot)))
+
+	for i, b := range bars {
+		x0 := left + int(float64(i)*slot)
+		x1 := left + int(float64(i+1)*slot)
+		gap := max(1, (x1-x0)/5)
+		if highest > 0 && b.Value > 0 {
+			h := max(1, int(b.Value/highest*float64(plotHeight)))
+			fillRect(img, x0+gap, bottom-h, x1-x0-2*gap, h, barColor)
+		}
+		if i%every == 0 {
+			lx := x0 + (x1-x0-textWidth(b.Label, textScale))/2
+			drawText(img, max(0, lx), bottom+8, b.Label, textScale, foreground)
+		}
+	}
+	return img
+}
+
+// Heatmap renders values[row][col] as a grid of cells shaded by value.
+func Heatmap(title string, rows, cols []string, values [][]float64) *image.RGBA {
+	img := newCanvas(width, height)
+	drawText(img, padding, padding, title, textScale, foreground)
+
+	rowLabel := 0
+	for _, r := range rows {
+		rowLabel = max(rowLabel, textWidth(r, textScale))
+	}
+	colLabel := 0
+	for _, c := range cols {
+		colLabel = max(colLabel, textWidth(c, textScale))
+	}
+
+	left := padding + rowLabel + 8
+	top := padding + textHeight(textScale) + 16
+	bottom := height - padding - textHeight(textScale) - 8
+	right := width - padding
+	if len(rows) == 0 || len(cols) == 0 {
+		return img
+	}
+
+	cellW := (right - left) / len(cols)
+	cellH := (bottom - top) / len(rows)
+	highest := 0.0
+	for _, row := range values {
+		for _, v := range row {
+			highest = math.Max(highest, v)
+		}
+	}
+
+	every := max(1, int(math.Ceil(float64(colLabel+6)/float64(cellW))))
+	for c, label := range cols {
+		if c%every == 0 {
+			drawText(img, left+c*cellW+(cellW-textWidth(label, textScale))/2, top+len(rows)*cellH+8, label, textScale, foreground)
+		}
+	}
+	for r, label := range rows {
+		y := top + r*cellH
+		drawText(img, padding, y+(cellH-textHeight(textScale))/2, label, textScale, foreground)
+		for c := range cols {
+			v := 0.0
+			if r < len(values) && c < len(values[r]) {
+				v = values[r][c]
+			}
+			shade := heatLow
+			if highest > 0 && v > 0 {
+				shade = blend(heatLow, heatHigh, 0.2+0.8*v/highest)
+			}
+			fillRect(img, left+c*cellW+1, y+1, cellW-2, cellH-2, shade)
+		}
+	}
+	return img
+}
+
+// EncodePNG encodes img as PNG.
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func newCanvas(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+	return img
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*t) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
@@ -0,0 +1,51 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"bigbrother/internal/processor"
+)
+
+func TestBars_DrawsScaledBars(t *testing.T) {
+	img := Bars("Test", []Bar{{Label: "A", Value: 1}, {Label: "B", Value: 0}, {Label: "C", Value: 2}})
+
+	// Sample just above the axis in the middle of each slot.
+	colored := 0
+	for _, x := range []int{width / 6 * 1, width / 6 * 3, width / 6 * 5} {
+		if img.RGBAAt(x+10, height-padding-textHeight(textScale)-10) == barColor {
+			colored++
+		}
+	}
+	if colored != 2 {
+		t.Fatalf("expected 2 drawn bars, got %d", colored)
+	}
+}
+
+func TestMismatchHeatmap_EncodesPNG(t *testing.T) {
+	friday := time.Date(2026, 2, 6, 22, 15, 0, 0, time.UTC)
+	img := MismatchHeatmap([]processor.ReceiptReport{
+		{ReceiptNo: "1", Issued: friday, Match: false},
+		{ReceiptNo: "2", Issued: friday, Match: true},
+	})
+
+	data, err := EncodePNG(img)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.Bounds().Dx() != width || decoded.Bounds().Dy() != height {
+		t.Fatalf("unexpected size: %v", decoded.Bounds())
+	}
+}
+
+func TestMondayFirst(t *testing.T) {
+	if mondayFirst(time.Monday) != 0 || mondayFirst(time.Sunday) != 6 {
+		t.Fatal("expected Monday first ordering")
+	}
+}
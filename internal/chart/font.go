@@ -0,0 +1,96 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// glyphs is a 3x5 pixel font covering what chart labels need. Lower case
+// letters are drawn as upper case, unknown runes as blanks.
+var glyphs = map[rune][5]string{
+	'0': {"111", "101", "101", "101", "111"},
+	'1': {"010", "110", "010", "010", "111"},
+	'2': {"111", "001", "111", "100", "111"},
+	'3': {"111", "001", "011", "001", "111"},
+	'4': {"101", "101", "111", "001", "001"},
+	'5': {"111", "100", "111", "001", "111"},
+	'6': {"111", "100", "111", "101", "111"},
+	'7': {"111", "001", "010", "010", "010"},
+	'8': {"111", "101", "111", "101", "111"},
+	'9': {"111", "101", "111", "001", "111"},
+	'A': {"010", "101", "111", "101", "101"},
+	'B': {"110", "101", "110", "101", "110"},
+	'C': {"011", "100", "100", "100", "011"},
+	'D': {"110", "101", "101", "101", "110"},
+	'E': {"111", "100", "110", "100", "111"},
+	'F': {"111", "100", "110", "100", "100"},
+	'G': {"011", "100", "101", "101", "011"},
+	'H': {"101", "101", "111", "101", "101"},
+	'I': {"111", "010", "010", "010", "111"},
+	'J': {"001", "001", "001", "101", "010"},
+	'K': {"101", "101", "110", "101", "101"},
+	'L': {"100", "100", "100", "100", "111"},
+	'M': {"101", "111", "111", "101", "101"},
+	'N': {"110", "101", "101", "101", "101"},
+	'O': {"010", "101", "101", "101", "010"},
+	'P': {"110", "101", "110", "100", "100"},
+	'Q': {"010", "101", "101", "110", "011"},
+	'R': {"110", "101", "110", "101", "101"},
+	'S': {"011", "100", "010", "001", "110"},
+	'T': {"111", "010", "010", "010", "010"},
+	'U': {"101", "101", "101", "101", "111"},
+	'V': {"101", "101", "101", "101", "010"},
+	'W': {"101", "101", "111", "111", "101"},
+	'X': {"101", "101", "010", "101", "101"},
+	'Y': {"101", "101", "010", "010", "010"},
+	'Z': {"111", "001", "010", "100", "111"},
+	'.': {"000", "000", "000", "000", "010"},
+	',': {"000", "000", "000", "010", "100"},
+	':': {"000", "010", "000", "010", "000"},
+	'-': {"000", "000", "111", "000", "000"},
+	'/': {"001", "001", "010", "100", "100"},
+	'%': {"101", "001", "010", "100", "101"},
+	'#': {"101", "111", "101", "111", "101"},
+	'(': {"001", "010", "010", "010", "001"},
+	')': {"100", "010", "010", "010", "100"},
+	'x': {"000", "101", "010", "101", "000"},
+}
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// textWidth returns the width in pixels of s drawn at the given scale.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * scale
+}
+
+func textHeight(scale int) int {
+	return glyphHeight * scale
+}
+
+// drawText draws s with its top left corner at x, y.
+func drawText(img *image.RGBA, x, y int, s string, scale int, c color.Color) {
+	for _, r := range s {
+		g, ok := glyphs[r]
+		if !ok {
+			g, ok = glyphs[[]rune(strings.ToUpper(string(r)))[0]]
+		}
+		if ok {
+			for row, bits := range g {
+				for col, bit := range bits {
+					if bit == '1' {
+						fillRect(img, x+col*scale, y+row*scale, scale, scale, c)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
@@ -0,0 +1,62 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"bigbrother/internal/history"
+	"bigbrother/internal/processor"
+)
+
+var weekdayLabels = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// MismatchesPerDay charts the mismatch count of every day in st.
+func MismatchesPerDay(st history.Stats) *image.RGBA {
+	bars := make([]Bar, 0, len(st.Days))
+	for _, d := range st.Days {
+		bars = append(bars, Bar{Label: d.Date.Format("02.01"), Value: float64(d.Mismatches)})
+	}
+	title := fmt.Sprintf("Mismatches per day %s - %s", st.From.Format("02.01.2006"), st.To.AddDate(0, 0, -1).Format("02.01.2006"))
+	return Bars(title, bars)
+}
+
+// LitersPerHour charts the beer poured per hour of day. Receipts without a
+// parsed issue time are skipped.
+func LitersPerHour(receipts []processor.ReceiptReport) *image.RGBA {
+	var ml [24]int64
+	for _, r := range receipts {
+		if r.Issued.IsZero() {
+			continue
+		}
+		ml[r.Issued.Hour()] += r.BeerML
+	}
+	bars := make([]Bar, 24)
+	for hour := range bars {
+		bars[hour] = Bar{Label: fmt.Sprintf("%02d", hour), Value: float64(ml[hour]) / 1000.0}
+	}
+	return Bars("Beer liters per hour", bars)
+}
+
+// MismatchHeatmap charts mismatches by weekday and hour of day.
+func MismatchHeatmap(receipts []processor.ReceiptReport) *image.RGBA {
+	values := make([][]float64, 7)
+	for i := range values {
+		values[i] = make([]float64, 24)
+	}
+	for _, r := range receipts {
+		if !r.OpenMismatch() || r.Issued.IsZero() {
+			continue
+		}
+		values[mondayFirst(r.Issued.Weekday())][r.Issued.Hour()]++
+	}
+	cols := make([]string, 24)
+	for hour := range cols {
+		cols[hour] = fmt.Sprintf("%02d", hour)
+	}
+	return Heatmap("Mismatches by weekday and hour", weekdayLabels, cols, values)
+}
+
+func mondayFirst(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Mode string
@@ -23,6 +24,37 @@ type Config struct {
 
 	MaxFileBytes         int64
 	MaxDocsPerMinuteChat int
+
+	Audits       []string
+	VATRates     string
+	OpeningHours string
+	Holidays     string
+	Shifts       string
+
+	BottleLowStock      int64
+	OperatorMinReceipts int
+	ReportCharts        bool
+
+	ToleranceML    int64
+	Snark          string
+	Tone           string
+	PhrasesDir     string
+	Timezone       string
+	ColumnProfiles string
+
+	ArchiveMaxAge   time.Duration
+	ArchiveMaxBytes int64
+
+	StorageBackend string
+	S3Endpoint     string
+	S3Bucket       string
+	S3Region       string
+	S3AccessKey    string
+	S3SecretKey    string
+
+	EncryptionKeys    string
+	EncryptionRotate  bool
+	EncryptionRequire bool
 }
 
 func Load() (Config, error) {
@@ -76,6 +108,136 @@ func Load() (Config, error) {
 		maxDocsPerMinuteChat = n
 	}
 
+	audits := splitList(os.Getenv("AUDITS"))
+
+	bottleLowStock := int64(20)
+	if raw := strings.TrimSpace(os.Getenv("BOTTLE_LOW_STOCK")); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("invalid BOTTLE_LOW_STOCK: %s", raw)
+		}
+		bottleLowStock = n
+	}
+
+	operatorMinReceipts := 20
+	if raw := strings.TrimSpace(os.Getenv("OPERATOR_MIN_RECEIPTS")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return Config{}, fmt.Errorf("invalid OPERATOR_MIN_RECEIPTS: %s", raw)
+		}
+		operatorMinReceipts = n
+	}
+
+	reportCharts := false
+	if raw := strings.TrimSpace(os.Getenv("REPORT_CHARTS")); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid REPORT_CHARTS: %s", raw)
+		}
+		reportCharts = b
+	}
+
+	toleranceML := int64(0)
+	if raw := strings.TrimSpace(os.Getenv("TOLERANCE_ML")); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("invalid TOLERANCE_ML: %s", raw)
+		}
+		toleranceML = n
+	}
+
+	snark := strings.ToLower(strings.TrimSpace(os.Getenv("SNARK")))
+	switch snark {
+	case "":
+		snark = "mismatch"
+	case "off", "mismatch", "always":
+		// ok
+	default:
+		return Config{}, fmt.Errorf("invalid SNARK: %s", snark)
+	}
+
+	// Tones can come from phrase packs, so TONE is checked once they are
+	// loaded.
+	tone := strings.ToLower(strings.TrimSpace(os.Getenv("TONE")))
+	if tone == "" {
+		tone = "snarky"
+	}
+
+	timezone := strings.TrimSpace(os.Getenv("TIMEZONE"))
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return Config{}, fmt.Errorf("invalid TIMEZONE: %s", timezone)
+		}
+	}
+
+	archiveDays := 90
+	if raw := strings.TrimSpace(os.Getenv("ARCHIVE_RETENTION_DAYS")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("invalid ARCHIVE_RETENTION_DAYS: %s", raw)
+		}
+		archiveDays = n
+	}
+
+	archiveMaxBytes := int64(1024 * 1024 * 1024) // 1 GiB default
+	if raw := strings.TrimSpace(os.Getenv("ARCHIVE_MAX_BYTES")); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("invalid ARCHIVE_MAX_BYTES: %s", raw)
+		}
+		archiveMaxBytes = n
+	}
+
+	storageBackend := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_BACKEND")))
+	if storageBackend == "" {
+		storageBackend = "local"
+	}
+	s3Endpoint := strings.TrimSpace(os.Getenv("S3_ENDPOINT"))
+	s3Bucket := strings.TrimSpace(os.Getenv("S3_BUCKET"))
+	switch storageBackend {
+	case "local":
+		// ok
+	case "s3":
+		if s3Endpoint == "" || s3Bucket == "" {
+			return Config{}, errors.New("S3_ENDPOINT and S3_BUCKET are required for STORAGE_BACKEND=s3")
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported STORAGE_BACKEND: %s", storageBackend)
+	}
+
+	encryptionKeys := strings.TrimSpace(os.Getenv("ENCRYPTION_KEYS"))
+	if path := strings.TrimSpace(os.Getenv("ENCRYPTION_KEY_FILE")); path != "" {
+		if encryptionKeys != "" {
+			return Config{}, errors.New("set only one of ENCRYPTION_KEYS and ENCRYPTION_KEY_FILE")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("read ENCRYPTION_KEY_FILE: %w", err)
+		}
+		encryptionKeys = strings.TrimSpace(string(data))
+		if encryptionKeys == "" {
+			return Config{}, fmt.Errorf("ENCRYPTION_KEY_FILE is empty: %s", path)
+		}
+	}
+
+	encryptionRotate := false
+	if raw := strings.TrimSpace(os.Getenv("ENCRYPTION_ROTATE")); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid ENCRYPTION_ROTATE: %s", raw)
+		}
+		encryptionRotate = b
+	}
+
+	encryptionRequire := false
+	if raw := strings.TrimSpace(os.Getenv("ENCRYPTION_REQUIRE")); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid ENCRYPTION_REQUIRE: %s", raw)
+		}
+		encryptionRequire = b
+	}
+
 	return Config{
 		Token:                token,
 		DataDir:              dataDir,
@@ -83,5 +245,41 @@ func Load() (Config, error) {
 		PublicURL:            publicURL,
 		MaxFileBytes:         maxFileBytes,
 		MaxDocsPerMinuteChat: maxDocsPerMinuteChat,
+		Audits:               audits,
+		VATRates:             strings.TrimSpace(os.Getenv("VAT_RATES")),
+		OpeningHours:         strings.TrimSpace(os.Getenv("OPENING_HOURS")),
+		Holidays:             strings.TrimSpace(os.Getenv("HOLIDAYS")),
+		Shifts:               strings.TrimSpace(os.Getenv("SHIFTS")),
+		BottleLowStock:       bottleLowStock,
+		OperatorMinReceipts:  operatorMinReceipts,
+		ReportCharts:         reportCharts,
+		ToleranceML:          toleranceML,
+		Snark:                snark,
+		Tone:                 tone,
+		PhrasesDir:           strings.TrimSpace(os.Getenv("PHRASES_DIR")),
+		Timezone:             timezone,
+		ColumnProfiles:       strings.TrimSpace(os.Getenv("COLUMN_PROFILES")),
+		ArchiveMaxAge:        time.Duration(archiveDays) * 24 * time.Hour,
+		ArchiveMaxBytes:      archiveMaxBytes,
+		StorageBackend:       storageBackend,
+		S3Endpoint:           s3Endpoint,
+		S3Bucket:             s3Bucket,
+		S3Region:             strings.TrimSpace(os.Getenv("S3_REGION")),
+		S3AccessKey:          strings.TrimSpace(os.Getenv("S3_ACCESS_KEY")),
+		S3SecretKey:          strings.TrimSpace(os.Getenv("S3_SECRET_KEY")),
+		EncryptionKeys:       encryptionKeys,
+		EncryptionRotate:     encryptionRotate,
+		EncryptionRequire:    encryptionRequire,
 	}, nil
 }
+
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
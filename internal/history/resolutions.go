@@ -0,0 +1,185 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"bigbrother/internal/processor"
+)
+
+const (
+	ActionResolve = "resolve"
+	ActionComment = "comment"
+)
+
+// Action is one entry of the append-only resolution log. Actions logged
+// before receipts were told apart by register and day have only ReceiptNo.
+type Action struct {
+	At        time.Time `json:"at"`
+	Kind      string    `json:"action"`
+	UserID    int64     `json:"user_id"`
+	User      string    `json:"user"`
+	ReceiptNo string    `json:"receipt_no"`
+	Register  string    `json:"register,omitempty"`
+	Date      string    `json:"date,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+}
+
+// Ref returns the receipt the action is about.
+func (a Action) Ref() processor.ReceiptRef {
+	return processor.ReceiptRef{Register: a.Register, No: a.ReceiptNo, Date: a.Date}
+}
+
+// Resolution is the current state of a resolved receipt.
+type Resolution struct {
+	Ref     processor.ReceiptRef
+	Reason  string
+	Comment string
+	User    string
+	At      time.Time
+}
+
+// Resolutions are the current resolutions of a chat by receipt. They
+// satisfy processor.Resolutions.
+type Resolutions map[processor.ReceiptRef]Resolution
+
+// Lookup returns the resolution of rec, keyed by its register, number and
+// day.
+func (r Resolutions) Lookup(rec processor.ReceiptReport) (Resolution, bool) {
+	res, ok := r[rec.Ref()]
+	return res, ok
+}
+
+func (r Resolutions) Reason(rec processor.ReceiptReport) string {
+	res, _ := r.Lookup(rec)
+	return res.Reason
+}
+
+// ResolutionStore appends resolution actions to DATA_DIR/resolutions/<chat>.jsonl.
+// The log is never rewritten; the current state is replayed from it.
+type ResolutionStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewResolutionStore(dataDir string) *ResolutionStore {
+	return &ResolutionStore{dir: filepath.Join(dataDir, "resolutions")}
+}
+
+// Append writes a to the chat log. Comments need the receipt to be resolved.
+func (s *ResolutionStore) Append(chatID int64, a Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a.Kind == ActionComment {
+		actions, err := s.read(chatID)
+		if err != nil {
+			return err
+		}
+		if _, ok := replay(actions)[a.Ref()]; !ok {
+			return ErrNotFound
+		}
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+	line, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("encode action: %w", err)
+	}
+	f, err := os.OpenFile(s.path(chatID), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open resolution log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write resolution log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("sync resolution log: %w", err)
+	}
+	return f.Close()
+}
+
+// Log returns every action of the chat, oldest first.
+func (s *ResolutionStore) Log(chatID int64) ([]Action, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(chatID)
+}
+
+// Resolved returns the current resolution of every resolved receipt.
+func (s *ResolutionStore) Resolved(chatID int64) (Resolutions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actions, err := s.read(chatID)
+	if err != nil {
+		return nil, err
+	}
+	return replay(actions), nil
+}
+
+func replay(actions []Action) Resolutions {
+	resolved := make(Resolutions)
+	for _, a := range actions {
+		switch a.Kind {
+		case ActionResolve:
+			resolved[a.Ref()] = Resolution{
+				Ref:     a.Ref(),
+				Reason:  a.Reason,
+				Comment: a.Comment,
+				User:    a.User,
+				At:      a.At,
+			}
+		case ActionComment:
+			if res, ok := resolved[a.Ref()]; ok {
+				res.Comment = a.Comment
+				resolved[a.Ref()] = res
+			}
+		}
+	}
+	return resolved
+}
+
+func (s *ResolutionStore) read(chatID int64) ([]Action, error) {
+	f, err := os.Open(s.path(chatID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open resolution log: %w", err)
+	}
+	defer f.Close()
+
+	var actions []Action
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var a Action
+		if err := json.Unmarshal(sc.Bytes(), &a); err != nil {
+			return nil, fmt.Errorf("decode resolution log: %w", err)
+		}
+		actions = append(actions, a)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read resolution log: %w", err)
+	}
+	return actions, nil
+}
+
+func (s *ResolutionStore) path(chatID int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(chatID, 10)+".jsonl")
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bigbrother/internal/processor"
+)
+
+func TestResolutionStore_AppendAndReplay(t *testing.T) {
+	store := NewResolutionStore(t.TempDir())
+	at := time.Date(2026, 2, 7, 9, 0, 0, 0, time.UTC)
+
+	if err := store.Append(1, Action{Kind: ActionComment, ReceiptNo: "R1", Comment: "early"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected comment on unresolved receipt to fail, got: %v", err)
+	}
+	if err := store.Append(1, Action{At: at, Kind: ActionResolve, User: "jana", ReceiptNo: "R1", Reason: "explained"}); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := store.Append(1, Action{At: at, Kind: ActionResolve, User: "petr", ReceiptNo: "R1", Reason: "staff_error"}); err != nil {
+		t.Fatalf("resolve again: %v", err)
+	}
+	if err := store.Append(1, Action{At: at, Kind: ActionComment, User: "petr", ReceiptNo: "R1", Comment: "new bartender"}); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+
+	resolved, err := store.Resolved(1)
+	if err != nil {
+		t.Fatalf("resolved: %v", err)
+	}
+	res := resolved[processor.ReceiptRef{No: "R1"}]
+	if res.Reason != "staff_error" || res.User != "petr" || res.Comment != "new bartender" {
+		t.Fatalf("unexpected resolution: %+v", res)
+	}
+
+	log, err := store.Log(1)
+	if err != nil || len(log) != 3 {
+		t.Fatalf("expected 3 logged actions, got %d (%v)", len(log), err)
+	}
+	data, err := os.ReadFile(store.path(1))
+	if err != nil || strings.Count(string(data), "\n") != 3 {
+		t.Fatalf("expected an append-only log with 3 lines, got: %q (%v)", data, err)
+	}
+
+	if other, err := store.Resolved(2); err != nil || len(other) != 0 {
+		t.Fatalf("expected empty resolutions for another chat, got: %v (%v)", other, err)
+	}
+}
+
+func TestResolutions_Lookup(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 10, 0, 0, 0, time.UTC) }
+	resolved := Resolutions{
+		{Register: "Pokladna 1", No: "100", Date: "2026-02-06"}: {Reason: "explained"},
+		{No: "7"}: {Reason: "staff_error"},
+	}
+
+	for _, tc := range []struct {
+		rec  processor.ReceiptReport
+		want string
+	}{
+		{processor.ReceiptReport{Register: "Pokladna 1", ReceiptNo: "100", Issued: day(6)}, "explained"},
+		{processor.ReceiptReport{Register: "Pokladna 2", ReceiptNo: "100", Issued: day(6)}, ""},
+		{processor.ReceiptReport{Register: "Pokladna 1", ReceiptNo: "100", Issued: day(9)}, ""},
+		{processor.ReceiptReport{ReceiptNo: "7"}, "staff_error"},
+		{processor.ReceiptReport{Register: "Pokladna 1", ReceiptNo: "7", Issued: day(6)}, ""},
+	} {
+		if got := resolved.Reason(tc.rec); got != tc.want {
+			t.Fatalf("%s on %s at %s: expected %q, got %q", tc.rec.ReceiptNo, tc.rec.Register, tc.rec.Issued, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bigbrother/internal/i18n"
+	"bigbrother/internal/processor"
+)
+
+// DayStat sums the checked receipts issued on one calendar day.
+type DayStat struct {
+	Date       time.Time
+	Receipts   int
+	Mismatches int
+	BeerML     int64
+}
+
+func (d DayStat) MismatchRate() float64 {
+	if d.Receipts == 0 {
+		return 0
+	}
+	return float64(d.Mismatches) / float64(d.Receipts)
+}
+
+// Stats sums the report history over a date range.
+type Stats struct {
+	From       time.Time
+	To         time.Time
+	Reports    int
+	Receipts   int
+	Mismatches int
+	BeerML     int64
+	Bottles    map[int64]int64
+	Days       []DayStat
+}
+
+func (s Stats) MismatchRate() float64 {
+	if s.Receipts == 0 {
+		return 0
+	}
+	return float64(s.Mismatches) / float64(s.Receipts)
+}
+
+// WorstDays returns up to n days with mismatches, most mismatches first.
+func (s Stats) WorstDays(n int) []DayStat {
+	var days []DayStat
+	for _, d := range s.Days {
+		if d.Mismatches > 0 {
+			days = append(days, d)
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		if days[i].Mismatches != days[j].Mismatches {
+			return days[i].Mismatches > days[j].Mismatches
+		}
+		return days[i].MismatchRate() > days[j].MismatchRate()
+	})
+	if len(days) > n {
+		days = days[:n]
+	}
+	return days
+}
+
+// Summarize collects receipts issued between from and to (exclusive) across
+// records. Days holds every day of the range, including empty ones.
+func Summarize(records []Record, from, to time.Time) Stats {
+	from = startOfDay(from)
+	to = startOfDay(to)
+	st := Stats{From: from, To: to, Bottles: make(map[int64]int64)}
+
+	byDay := make(map[time.Time]*DayStat)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		st.Days = append(st.Days, DayStat{Date: day})
+	}
+	for i := range st.Days {
+		byDay[st.Days[i].Date] = &st.Days[i]
+	}
+
+	receipts, reports := collect(records, from, to)
+	st.Reports = reports
+	for _, dr := range receipts {
+		r := dr.receipt
+		d := byDay[dr.day]
+		d.Receipts++
+		d.BeerML += r.BeerML
+		st.Receipts++
+		st.BeerML += r.BeerML
+		if r.OpenMismatch() {
+			d.Mismatches++
+			st.Mismatches++
+		}
+		for size, count := range r.BottleByML {
+			st.Bottles[size] += count
+		}
+	}
+	return st
+}
+
+// Receipts returns the checked receipts of records issued between from and
+// to (exclusive), each receipt once.
+func Receipts(records []Record, from, to time.Time) []processor.ReceiptReport {
+	dated, _ := collect(records, startOfDay(from), startOfDay(to))
+	out := make([]processor.ReceiptReport, 0, len(dated))
+	for _, dr := range dated {
+		out = append(out, dr.receipt)
+	}
+	return out
+}
+
+// Span returns the day range covering every receipt of records.
+func Span(records []Record) (time.Time, time.Time) {
+	var from, to time.Time
+	for _, rec := range records {
+		for _, r := range rec.Report.Receipts {
+			day := receiptDay(rec, r)
+			if from.IsZero() || day.Before(from) {
+				from = day
+			}
+			if !day.Before(to) {
+				to = day.AddDate(0, 0, 1)
+			}
+		}
+	}
+	return from, to
+}
+
+// Operators groups the checked receipts of records by operator, counting
+// each receipt once like Summarize does. It also returns the number of
+// reports the receipts came from.
+func Operators(records []Record) ([]processor.OperatorStat, int) {
+	var receipts []processor.ReceiptReport
+	reports := make(map[int]bool)
+	for _, dr := range uniqueReceipts(records) {
+		receipts = append(receipts, dr.receipt)
+		reports[dr.report] = true
+	}
+	return processor.OperatorStats(receipts), len(reports)
+}
+
+type datedReceipt struct {
+	day     time.Time
+	report  int
+	receipt processor.ReceiptReport
+}
+
+// collect returns the receipts issued in [from, to) and the number of reports
+// they came from.
+func collect(records []Record, from, to time.Time) ([]datedReceipt, int) {
+	var out []datedReceipt
+	reports := make(map[int]bool)
+	for _, dr := range uniqueReceipts(records) {
+		if dr.day.Before(from) || !dr.day.Before(to) {
+			continue
+		}
+		reports[dr.report] = true
+		out = append(out, dr)
+	}
+	return out, len(reports)
+}
+
+// uniqueReceipts returns the checked receipts of records, each once. Of a
+// file uploaded several times only the newest report is used, and a receipt
+// present in several reports, e.g. overlapping exports, is taken from the
+// newest one. Receipts without a parsed issue time count on the day the
+// report was created.
+func uniqueReceipts(records []Record) []datedReceipt {
+	type receiptKey struct {
+		register string
+		no       string
+		issuedAt string
+	}
+	newest := make(map[string]int)
+	for _, rec := range records {
+		if rec.SHA256 != "" {
+			newest[rec.SHA256] = rec.ID
+		}
+	}
+
+	index := make(map[receiptKey]int)
+	var out []datedReceipt
+	for _, rec := range records {
+		if rec.SHA256 != "" && newest[rec.SHA256] != rec.ID {
+			continue
+		}
+		for _, r := range rec.Report.Receipts {
+			dr := datedReceipt{day: receiptDay(rec, r), report: rec.ID, receipt: r}
+			key := receiptKey{register: r.Register, no: r.ReceiptNo, issuedAt: r.IssuedAt}
+			if i, ok := index[key]; ok {
+				out[i] = dr
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, dr)
+		}
+	}
+	return out
+}
+
+func receiptDay(rec Record, r processor.ReceiptReport) time.Time {
+	if r.Issued.IsZero() {
+		return startOfDay(rec.CreatedAt)
+	}
+	return startOfDay(r.Issued)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the mismatches of each day as one character, scaled to
+// the worst day. Days without checked receipts are shown as a dot.
+func (s Stats) Sparkline() string {
+	highest := 0
+	for _, d := range s.Days {
+		highest = max(highest, d.Mismatches)
+	}
+
+	var b strings.Builder
+	for _, d := range s.Days {
+		switch {
+		case d.Receipts == 0:
+			b.WriteRune('·')
+		case highest == 0:
+			b.WriteRune(sparkLevels[0])
+		default:
+			b.WriteRune(sparkLevels[d.Mismatches*(len(sparkLevels)-1)/highest])
+		}
+	}
+	return b.String()
+}
+
+// FormatStats renders the /stats reply with p.
+func FormatStats(s Stats, p i18n.Printer) string {
+	last := s.To.AddDate(0, 0, -1)
+	if s.Receipts == 0 {
+		return p.Sprintf("No checked receipts between %s and %s.", s.From.Format("02.01.2006"), last.Format("02.01.2006"))
+	}
+
+	var b strings.Builder
+	b.WriteString(p.Plural(s.Reports, "Stats %s - %s (%d report)\n", "Stats %s - %s (%d reports)\n", s.From.Format("02.01.2006"), last.Format("02.01.2006"), s.Reports))
+	b.WriteString(p.Sprintf("Receipts checked: %d\n", s.Receipts))
+	b.WriteString(p.Sprintf("Mismatches: %d (%.1f%%)\n", s.Mismatches, s.MismatchRate()*100))
+	b.WriteString(p.Sprintf("Beer: %.1fL\n", float64(s.BeerML)/1000.0))
+
+	if len(s.Bottles) > 0 {
+		sizes := make([]int64, 0, len(s.Bottles))
+		for size := range s.Bottles {
+			sizes = append(sizes, size)
+		}
+		sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+		parts := make([]string, 0, len(sizes))
+		for _, size := range sizes {
+			parts = append(parts, fmt.Sprintf("%.1fL x%d", float64(size)/1000.0, s.Bottles[size]))
+		}
+		b.WriteString(p.Sprintf("Bottles: %s\n", strings.Join(parts, ", ")))
+	}
+
+	b.WriteString(p.Sprintf("\nMismatches per day:\n%s\n%s .. %s\n", s.Sparkline(), s.From.Format("02.01."), last.Format("02.01.")))
+
+	if worst := s.WorstDays(3); len(worst) > 0 {
+		b.WriteString(p.Sprintf("\nWorst days:\n"))
+		for _, d := range worst {
+			b.WriteString(p.Sprintf("%s %s: %d of %d receipts (%.0f%%)\n", p.Text(d.Date.Format("Mon")), d.Date.Format("02.01."), d.Mismatches, d.Receipts, d.MismatchRate()*100))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
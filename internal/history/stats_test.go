@@ -0,0 +1,77 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"bigbrother/internal/processor"
+)
+
+func TestSummarize(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2026, 2, d, h, 0, 0, 0, time.UTC) }
+	records := []Record{
+		{ID: 1, CreatedAt: day(7, 8), Report: processor.Report{Receipts: []processor.ReceiptReport{
+			{ReceiptNo: "1", IssuedAt: "a", Issued: day(5, 20), BeerML: 1000, BottleByML: map[int64]int64{1000: 1}, Match: true},
+			{ReceiptNo: "2", IssuedAt: "b", Issued: day(6, 21), BeerML: 500, Match: false},
+			{ReceiptNo: "3", IssuedAt: "c", Issued: day(1, 21), BeerML: 500, Match: false},
+		}}},
+		// Overlapping export: receipt 2 again, now matching.
+		{ID: 2, CreatedAt: day(8, 8), Report: processor.Report{Receipts: []processor.ReceiptReport{
+			{ReceiptNo: "2", IssuedAt: "b", Issued: day(6, 21), BeerML: 500, BottleByML: map[int64]int64{500: 1}, Match: true},
+			{ReceiptNo: "4", BeerML: 1500, Match: false},
+			{ReceiptNo: "5", IssuedAt: "e", Issued: day(7, 22), BeerML: 300, Match: false},
+		}}},
+	}
+
+	st := Summarize(records, day(5, 0), day(9, 0))
+	if len(st.Days) != 4 || st.Reports != 2 {
+		t.Fatalf("unexpected range: %d days, %d reports", len(st.Days), st.Reports)
+	}
+	if st.Receipts != 4 || st.Mismatches != 2 || st.BeerML != 3300 {
+		t.Fatalf("unexpected totals: %+v", st)
+	}
+	if st.Bottles[1000] != 1 || st.Bottles[500] != 1 {
+		t.Fatalf("unexpected bottles: %v", st.Bottles)
+	}
+	if st.Days[1].Mismatches != 0 || st.Days[1].Receipts != 1 {
+		t.Fatalf("expected newest copy of receipt 2 to win: %+v", st.Days[1])
+	}
+	// Receipt 4 has no issue time and counts on the report day.
+	if st.Days[3].Receipts != 1 || st.Days[3].Mismatches != 1 {
+		t.Fatalf("unexpected fallback day: %+v", st.Days[3])
+	}
+
+	if got := st.Sparkline(); got != "▁▁██" {
+		t.Fatalf("unexpected sparkline: %q", got)
+	}
+
+	worst := st.WorstDays(5)
+	if len(worst) != 2 || !worst[0].Date.Equal(day(7, 0)) {
+		t.Fatalf("unexpected worst days: %+v", worst)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	receipts := []processor.ReceiptReport{
+		{ReceiptNo: "1", Register: "A", IssuedAt: "a", Operator: "Jana", Match: true},
+		{ReceiptNo: "1", Register: "B", IssuedAt: "a", Operator: "Petr", Match: false},
+	}
+	records := []Record{
+		{ID: 1, SHA256: "x", Report: processor.Report{Receipts: receipts}},
+		// The same file again, and an overlapping export with receipt 1 of A.
+		{ID: 2, SHA256: "x", Report: processor.Report{Receipts: receipts}},
+		{ID: 3, SHA256: "y", Report: processor.Report{Receipts: []processor.ReceiptReport{
+			receipts[0],
+			{ReceiptNo: "2", Register: "A", IssuedAt: "b", Operator: "Jana", Match: false},
+		}}},
+	}
+
+	stats, reports := Operators(records)
+	if reports != 2 {
+		t.Fatalf("expected receipts from 2 reports, got %d", reports)
+	}
+	if len(stats) != 2 || stats[0] != (processor.OperatorStat{Name: "Jana", Receipts: 2, Mismatches: 1}) ||
+		stats[1] != (processor.OperatorStat{Name: "Petr", Receipts: 1, Mismatches: 1}) {
+		t.Fatalf("expected each receipt counted once, got %+v", stats)
+	}
+}
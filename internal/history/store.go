@@ -0,0 +1,238 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bigbrother/internal/processor"
+	"bigbrother/internal/storage"
+)
+
+var ErrNotFound = errors.New("report not found")
+
+// Record is a processed upload as kept in the report history.
+type Record struct {
+	ID        int              `json:"id"`
+	ChatID    int64            `json:"chat_id"`
+	CreatedAt time.Time        `json:"created_at"`
+	FileName  string           `json:"file_name"`
+	SHA256    string           `json:"sha256,omitempty"`
+	Report    processor.Report `json:"report"`
+}
+
+// Store keeps one JSON object per report under reports/<chat>/ in a
+// storage backend. Report IDs are sequential per chat. A hashes.json index
+// next to the reports maps the SHA-256 of each upload to its newest report.
+type Store struct {
+	mu      sync.Mutex
+	backend storage.Store
+}
+
+func NewStore(backend storage.Store) *Store {
+	return &Store{backend: backend}
+}
+
+// Save assigns the next report ID of the chat and persists the record.
+func (s *Store) Save(rec Record) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids(rec.ChatID)
+	if err != nil {
+		return Record{}, err
+	}
+	rec.ID = 1
+	if len(ids) > 0 {
+		rec.ID = ids[len(ids)-1] + 1
+	}
+	return rec, s.write(rec)
+}
+
+// Replace overwrites the existing report rec.ID, e.g. when an upload is
+// processed again.
+func (s *Store) Replace(rec Record) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _, err := s.backend.Get(context.Background(), s.recordKey(rec.ChatID, rec.ID))
+	if errors.Is(err, storage.ErrNotExist) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, fmt.Errorf("read report %d: %w", rec.ID, err)
+	}
+	body.Close()
+	return rec, s.write(rec)
+}
+
+func (s *Store) write(rec Record) error {
+	// Load the index before the new report exists, so that building it
+	// for older history does not pick the report up twice.
+	index, err := s.hashes(rec.ChatID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+	if err := s.backend.Put(context.Background(), s.recordKey(rec.ChatID, rec.ID), bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("save report: %w", err)
+	}
+
+	if rec.SHA256 == "" || index[rec.SHA256] >= rec.ID {
+		return nil
+	}
+	index[rec.SHA256] = rec.ID
+	return s.saveHashes(rec.ChatID, index)
+}
+
+func (s *Store) Get(chatID int64, id int) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(chatID, id)
+}
+
+// Latest returns the newest report of the chat.
+func (s *Store) Latest(chatID int64) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids(chatID)
+	if err != nil {
+		return Record{}, err
+	}
+	if len(ids) == 0 {
+		return Record{}, ErrNotFound
+	}
+	return s.read(chatID, ids[len(ids)-1])
+}
+
+// List returns every report of the chat, oldest first.
+func (s *Store) List(chatID int64) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids(chatID)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(ids))
+	for _, id := range ids {
+		rec, err := s.read(chatID, id)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// FindByHash returns the newest report of the chat made from a file with
+// the given SHA-256.
+func (s *Store) FindByHash(chatID int64, sha string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sha == "" {
+		return Record{}, ErrNotFound
+	}
+	index, err := s.hashes(chatID)
+	if err != nil {
+		return Record{}, err
+	}
+	id, ok := index[sha]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+	return s.read(chatID, id)
+}
+
+// hashes loads the hash index of the chat. A chat without one has no
+// reports yet.
+func (s *Store) hashes(chatID int64) (map[string]int, error) {
+	index := make(map[string]int)
+	body, _, err := s.backend.Get(context.Background(), s.hashesKey(chatID))
+	if errors.Is(err, storage.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read hash index: %w", err)
+	}
+	defer body.Close()
+	if err := json.NewDecoder(body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("decode hash index: %w", err)
+	}
+	return index, nil
+}
+
+func (s *Store) saveHashes(chatID int64, index map[string]int) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("encode hash index: %w", err)
+	}
+	if err := s.backend.Put(context.Background(), s.hashesKey(chatID), bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("save hash index: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) read(chatID int64, id int) (Record, error) {
+	body, _, err := s.backend.Get(context.Background(), s.recordKey(chatID, id))
+	if errors.Is(err, storage.ErrNotExist) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, fmt.Errorf("read report %d: %w", id, err)
+	}
+	defer body.Close()
+
+	var rec Record
+	if err := json.NewDecoder(body).Decode(&rec); err != nil {
+		return Record{}, fmt.Errorf("decode report %d: %w", id, err)
+	}
+	return rec, nil
+}
+
+func (s *Store) ids(chatID int64) ([]int, error) {
+	objects, err := s.backend.List(context.Background(), s.chatPrefix(chatID))
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+
+	var ids []int
+	for _, obj := range objects {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(obj.Key, s.chatPrefix(chatID)), ".json")
+		if !ok {
+			continue
+		}
+		id, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (s *Store) chatPrefix(chatID int64) string {
+	return "reports/" + strconv.FormatInt(chatID, 10) + "/"
+}
+
+func (s *Store) recordKey(chatID int64, id int) string {
+	return s.chatPrefix(chatID) + fmt.Sprintf("%06d.json", id)
+}
+
+func (s *Store) hashesKey(chatID int64) string {
+	return s.chatPrefix(chatID) + "hashes.json"
+}
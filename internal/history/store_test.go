@@ -0,0 +1,87 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bigbrother/internal/processor"
+	"bigbrother/internal/storage"
+)
+
+func TestStore_SaveAndList(t *testing.T) {
+	store := NewStore(storage.NewLocalStore(t.TempDir()))
+	now := time.Date(2026, 2, 6, 22, 0, 0, 0, time.UTC)
+
+	if _, err := store.Latest(1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found on empty history, got: %v", err)
+	}
+
+	first, err := store.Save(Record{ChatID: 1, CreatedAt: now, FileName: "a.csv", Report: processor.Report{TotalReceipts: 3}})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	second, err := store.Save(Record{ChatID: 1, CreatedAt: now, FileName: "b.csv", Report: processor.Report{TotalReceipts: 5, MismatchCount: 1}})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	other, err := store.Save(Record{ChatID: 2, CreatedAt: now, FileName: "c.csv"})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 || other.ID != 1 {
+		t.Fatalf("unexpected ids: %d %d %d", first.ID, second.ID, other.ID)
+	}
+
+	latest, err := store.Latest(1)
+	if err != nil || latest.ID != 2 || latest.Report.MismatchCount != 1 {
+		t.Fatalf("unexpected latest: %+v (%v)", latest, err)
+	}
+	records, err := store.List(1)
+	if err != nil || len(records) != 2 || records[0].FileName != "a.csv" {
+		t.Fatalf("unexpected list: %+v (%v)", records, err)
+	}
+	if _, err := store.Get(1, 9); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
+
+func TestStore_FindByHash(t *testing.T) {
+	store := NewStore(storage.NewLocalStore(t.TempDir()))
+	for _, sha := range []string{"aa", "bb", "aa"} {
+		if _, err := store.Save(Record{ChatID: 1, SHA256: sha}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	rec, err := store.FindByHash(1, "aa")
+	if err != nil || rec.ID != 3 {
+		t.Fatalf("expected newest report with the hash, got: %+v (%v)", rec, err)
+	}
+	if _, err := store.FindByHash(1, "cc"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	if _, err := store.FindByHash(2, "aa"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected hashes to be per chat, got: %v", err)
+	}
+}
+
+func TestStore_Replace(t *testing.T) {
+	store := NewStore(storage.NewLocalStore(t.TempDir()))
+	first, err := store.Save(Record{ChatID: 1, SHA256: "aa", Report: processor.Report{TotalReceipts: 3}})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	first.Report.TotalReceipts = 4
+	if _, err := store.Replace(first); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	records, err := store.List(1)
+	if err != nil || len(records) != 1 || records[0].Report.TotalReceipts != 4 {
+		t.Fatalf("expected the report to be replaced, got: %+v (%v)", records, err)
+	}
+	if _, err := store.Replace(Record{ChatID: 1, ID: 7}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
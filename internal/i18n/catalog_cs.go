@@ -0,0 +1,303 @@
+package i18n
+
+var czechMessages = map[string]string{
+	// Bot replies.
+	"Send me an .xlsx or .csv file and I will process it.":            "Pošlete mi soubor .xlsx nebo .csv a zpracuji ho.",
+	"Unknown command. Use /help.":                                     "Neznámý příkaz. Použijte /help.",
+	"Too many uploads. Try again in %ds.":                             "Příliš mnoho souborů. Zkuste to znovu za %d s.",
+	"Please upload a .xlsx or .csv file.":                             "Nahrajte prosím soubor .xlsx nebo .csv.",
+	"File is too large (%d bytes). Max allowed is %d bytes.":          "Soubor je příliš velký (%d bajtů). Maximum je %d bajtů.",
+	"Failed to download the file.":                                    "Soubor se nepodařilo stáhnout.",
+	"File rejected: %s.":                                              "Soubor odmítnut: %s.",
+	"Failed to process the file.":                                     "Soubor se nepodařilo zpracovat.",
+	"Report #%d":                                                      "Report č. %d",
+	"Report #%d not found.":                                           "Report č. %d nebyl nalezen.",
+	"This file was already processed on %s (report #%d).":             "Tento soubor už byl zpracován %s (report č. %d).",
+	"Show report":                                                     "Zobrazit report",
+	"Process again":                                                   "Zpracovat znovu",
+	"This upload is no longer available. Please send the file again.": "Tento soubor už není k dispozici. Pošlete ho prosím znovu.",
+	`Upload an .xlsx or .csv document. I will download and process it.
+
+/audits - list audits for this chat
+/audits on|off <name> - toggle an audit
+/settings - tolerance, snark, tone, time zone, audits, columns and language for this chat
+/snark - phrases of this chat, /snark add <kind> <text> to add one
+/tap <product> [<liters>l] - tap a new keg
+/kegs - keg status
+/stock - bottle stock
+/stock <size> <count|+delivered> [threshold] - set stock
+/count <size> <counted> - reconcile with a physical count
+/export [xlsx|json] [number] - download a report
+/operators [min receipts] - mismatch rate per operator
+/stats [7d|30d|from..to] - trends over stored reports
+/chart [days|hours|heatmap] [7d|30d|from..to|#report] - chart image
+/receipt <number> - look up a receipt in stored reports
+/resolve <number> - mark a mismatch as resolved
+/resolutions - who resolved what and when`: `Nahrajte dokument .xlsx nebo .csv. Stáhnu ho a zpracuji.
+
+/audits - kontroly v tomto chatu
+/audits on|off <název> - zapnout nebo vypnout kontrolu
+/settings - tolerance, poznámky, tón, časové pásmo, kontroly, sloupce a jazyk tohoto chatu
+/snark - fráze tohoto chatu, /snark add <druh> <text> přidá novou
+/tap <produkt> [<litry>l] - narazit nový sud
+/kegs - stav sudů
+/stock - zásoba lahví
+/stock <velikost> <počet|+dodáno> [limit] - nastavit zásobu
+/count <velikost> <napočítáno> - srovnat s fyzickou inventurou
+/export [xlsx|json] [číslo] - stáhnout report
+/operators [min. účtenek] - podíl rozdílů podle obsluhy
+/stats [7d|30d|od..do] - vývoj v uložených reportech
+/chart [days|hours|heatmap] [7d|30d|od..do|#report] - graf
+/receipt <číslo> - najít účtenku v uložených reportech
+/resolve <číslo> - označit rozdíl jako vyřešený
+/resolutions - kdo co vyřešil a kdy`,
+
+	// Settings.
+	`Usage:
+/settings - open the settings menu
+/settings tolerance <ml>|default
+/settings snark off|mismatch|always|default
+/settings tone <name>|default
+/settings timezone <Area/City>|default
+/settings columns <profile>|default
+/settings language en|cs|uk|default
+/settings audit <name> - toggle an audit
+/settings reset`: `Použití:
+/settings - otevřít nabídku nastavení
+/settings tolerance <ml>|default
+/settings snark off|mismatch|always|default
+/settings tone <název>|default
+/settings timezone <Oblast/Město>|default
+/settings columns <profil>|default
+/settings language en|cs|uk|default
+/settings audit <název> - zapnout nebo vypnout kontrolu
+/settings reset`,
+	"Failed to save the settings.":                "Nastavení se nepodařilo uložit.",
+	"Failed to save.":                             "Nepodařilo se uložit.",
+	"Unknown option.":                             "Neznámá volba.",
+	"Tolerance must be a number of milliliters.":  "Tolerance musí být počet mililitrů.",
+	"Unknown snark level %s.":                     "Neznámá úroveň poznámek %s.",
+	"Unknown time zone %s.":                       "Neznámé časové pásmo %s.",
+	"Unknown column profile %s.":                  "Neznámý profil sloupců %s.",
+	"Unknown audit %s.":                           "Neznámá kontrola %s.",
+	"Unknown language %s.":                        "Neznámý jazyk %s.",
+	"Unknown setting %s.":                         "Neznámé nastavení %s.",
+	"Unknown tone %s.":                            "Neznámý tón %s.",
+	"Tone: %s%s\n":                                "Tón: %s%s\n",
+	"Tone":                                        "Tón",
+	"Tone of the phrases in reports and remarks:": "Tón frází v reportech a poznámkách:",
+
+	// Phrases.
+	`Usage:
+/snark - list the phrases of this chat
+/snark add <kind> <text> - add a phrase
+/snark remove <kind> <number> - remove a phrase
+Kinds: match (opens a clean report), mismatch and snark-mismatch (remark after mismatches), snark-match (remark after a clean report)`: `Použití:
+/snark - fráze tohoto chatu
+/snark add <druh> <text> - přidat frázi
+/snark remove <druh> <číslo> - odebrat frázi
+Druhy: match (začátek reportu bez rozdílů), mismatch a snark-mismatch (poznámka po rozdílech), snark-match (poznámka po reportu bez rozdílů)`,
+	"Unknown phrase kind %s.":                                "Neznámý druh fráze %s.",
+	"Phrases can be at most %d characters long.":             "Fráze může mít nejvýše %d znaků.",
+	"A chat can have at most %d phrases. Remove some first.": "Chat může mít nejvýše %d frází. Nejdřív některé odeberte.",
+	"There is no %s phrase number %d.":                       "Fráze %s číslo %d neexistuje.",
+	"Tone: %s (change it with /settings tone)\n":             "Tón: %s (změníte ho příkazem /settings tone)\n",
+	"This chat has no phrases of its own.":                   "Tento chat nemá žádné vlastní fráze.",
+	" (default)":                                             " (výchozí)",
+	"server time":                                            "čas serveru",
+	"from Telegram":                                          "podle Telegramu",
+	"Settings for this chat:\n":                              "Nastavení tohoto chatu:\n",
+	"Tolerance: %d ml%s\n":                                   "Tolerance: %d ml%s\n",
+	"Snark: %s%s\n":                                          "Poznámky: %s%s\n",
+	"Time zone: %s%s\n":                                      "Časové pásmo: %s%s\n",
+	"Audits: %s%s\n":                                         "Kontroly: %s%s\n",
+	"Columns: %s%s\n":                                        "Sloupce: %s%s\n",
+	"Language: %s%s":                                         "Jazyk: %s%s",
+	"Tolerance":                                              "Tolerance",
+	"Snark":                                                  "Poznámky",
+	"Time zone":                                              "Časové pásmo",
+	"Audits":                                                 "Kontroly",
+	"Columns":                                                "Sloupce",
+	"Language":                                               "Jazyk",
+	"Reset":                                                  "Obnovit výchozí",
+	"« Back":                                                 "« Zpět",
+	"Default":                                                "Výchozí",
+	"Audits run on uploads:":                                 "Kontroly spouštěné u nahraných souborů:",
+	"Column names of the export:":                            "Názvy sloupců exportu:",
+	"Snarky remarks after a report:":                         "Jízlivé poznámky po reportu:",
+	"Beer vs bottles difference still counted as a match:":                              "Rozdíl mezi pivem a lahvemi, který se ještě počítá jako shoda:",
+	"Time zone for dates and day ranges (other zones: /settings timezone <Area/City>):": "Časové pásmo pro data a rozsahy dnů (jiná pásma: /settings timezone <Oblast/Město>):",
+	"Language of replies and reports (default: the language of your Telegram app):":     "Jazyk odpovědí a reportů (výchozí: jazyk vaší aplikace Telegram):",
+
+	// Commands.
+	"Usage: /audits on|off <name>":                                                "Použití: /audits on|off <název>",
+	"Unknown audit: %s":                                                           "Neznámá kontrola: %s",
+	"Audits for this chat:\n":                                                     "Kontroly tohoto chatu:\n",
+	"Failed to load the report.":                                                  "Report se nepodařilo načíst.",
+	"Failed to load report history.":                                              "Historii reportů se nepodařilo načíst.",
+	"Usage: /chart [days|hours|heatmap] [7d|30d|from..to|#report]":                "Použití: /chart [days|hours|heatmap] [7d|30d|od..do|#report]",
+	"No checked receipts to chart.":                                               "Žádné zkontrolované účtenky pro graf.",
+	"Usage: /export [xlsx|json] [report number]":                                  "Použití: /export [xlsx|json] [číslo reportu]",
+	"Nothing to export. Upload a file first or check the report number.":          "Není co exportovat. Nejdřív nahrajte soubor nebo zkontrolujte číslo reportu.",
+	"Failed to export the report.":                                                "Report se nepodařilo exportovat.",
+	"Usage: /tap <product> [<liters>l]":                                           "Použití: /tap <produkt> [<litry>l]",
+	"The keg size for %s is unknown. Use /tap %s <liters>l.":                      "Velikost sudu pro %s není známa. Použijte /tap %s <litry>l.",
+	"Failed to save the keg.":                                                     "Sud se nepodařilo uložit.",
+	"🛢️ Tapped %s (%.1fL).":                                                       "🛢️ Naraženo %s (%.1f l).",
+	"\nPrevious keg: %.1fL sold of %.1fL.":                                        "\nPředchozí sud: prodáno %.1f l z %.1f l.",
+	"Failed to load kegs.":                                                        "Sudy se nepodařilo načíst.",
+	"Failed to load bottle stock.":                                                "Zásobu lahví se nepodařilo načíst.",
+	"Usage: /stock <size> <count|+delivered> [threshold], e.g. /stock 0.5 120 20": "Použití: /stock <velikost> <počet|+dodáno> [limit], např. /stock 0.5 120 20",
+	"Failed to save bottle stock.":                                                "Zásobu lahví se nepodařilo uložit.",
+	"Usage: /count <size> <counted>, e.g. /count 0.5 96":                          "Použití: /count <velikost> <napočítáno>, např. /count 0.5 96",
+	"No stock recorded for %s bottles. Use /stock first.":                         "Pro lahve %s není zadaná zásoba. Nejdřív použijte /stock.",
+	"Failed to save the count.":                                                   "Inventuru se nepodařilo uložit.",
+	"Usage: /operators [minimum receipts]":                                        "Použití: /operators [minimum účtenek]",
+	"No operator data yet. The export needs an operator column (e.g. Pokladník).": "Zatím žádná data o obsluze. Export potřebuje sloupec s obsluhou (např. Pokladník).",
+	"%d. %s: %.1f%% (%d of %d receipts)\n":                                        "%d. %s: %.1f %% (%d z %d účtenek)\n",
+	"Usage: /receipt <number>":                                                    "Použití: /receipt <číslo>",
+	"Report #%d (%s, %s)\n\n%s":                                                   "Report č. %d (%s, %s)\n\n%s",
+	"Receipt %s not found in stored reports.":                                     "Účtenka %s v uložených reportech není.",
+	"and %d more":                                                "a %d dalších",
+	"Also in reports %s":                                         "Také v reportech %s",
+	"%s resolved by %s on %s":                                    "%s vyřešil(a) %s %s",
+	"Usage: /resolve <receipt number>":                           "Použití: /resolve <číslo účtenky>",
+	"Receipt %s has no mismatch to resolve.":                     "Účtenka %s nemá žádný rozdíl k vyřešení.",
+	"Receipt %s is too long for buttons.":                        "Účtenka %s je na tlačítka příliš dlouhá.",
+	"Resolve receipt %s as:":                                     "Vyřešit účtenku %s jako:",
+	"Receipt %s is resolved as %s. Change to:":                   "Účtenka %s je vyřešena jako %s. Změnit na:",
+	"Unknown action.":                                            "Neznámá akce.",
+	"Saved.":                                                     "Uloženo.",
+	"Receipt %s resolved as %s by %s.":                           "Účtenka %s vyřešena jako %s (%s).",
+	"Reply to this message to add a comment to receipt %s.":      "Odpovězte na tuto zprávu a přidejte komentář k účtence %s.",
+	"Receipt %s is not resolved.":                                "Účtenka %s není vyřešená.",
+	"Failed to save the comment.":                                "Komentář se nepodařilo uložit.",
+	"Comment added to receipt %s.":                               "Komentář k účtence %s přidán.",
+	"Failed to load the resolution log.":                         "Záznam vyřešení se nepodařilo načíst.",
+	"No mismatches resolved yet. Use /resolve <receipt number>.": "Zatím nejsou vyřešené žádné rozdíly. Použijte /resolve <číslo účtenky>.",
+	"Resolution log:\n":                                          "Záznam vyřešení:\n",
+	"comment on %s: %s\n":                                        "komentář k %s: %s\n",
+	"%s resolved as %s\n":                                        "%s vyřešeno jako %s\n",
+	"Usage: /stats [7d|30d|2026-02-01..2026-02-28]":              "Použití: /stats [7d|30d|2026-02-01..2026-02-28]",
+	"invalid date: %s":                                           "neplatné datum: %s",
+	"range ends before it starts":                                "rozsah končí dřív, než začíná",
+	"range is longer than %d days":                               "rozsah je delší než %d dní",
+	"invalid range: %s":                                          "neplatný rozsah: %s",
+
+	// Reports.
+	"No matching beer/PET rows found.":       "Nenalezeny žádné řádky s pivem nebo PET lahvemi.",
+	"All beer vs bottles match.":             "Pivo a lahve všude sedí.",
+	"Refunds: %d standalone, %d suspicious.": "Vratky: samostatné %d, podezřelé %d.",
+	"...truncated":                           "...zkráceno",
+	"\n===== Operators =====\n":              "\n===== Obsluha =====\n",
+	"\n===== Shifts =====\n":                 "\n===== Směny =====\n",
+	"\n===== What sold (%s) =====\n":         "\n===== Co se prodalo (%s) =====\n",
+	"By category: %s\n":                      "Podle kategorie: %s\n",
+	"%d more":                                "%d dalších",
+	"Operators":                              "Obsluha",
+	"Shifts":                                 "Směny",
+	"What sold (%s)":                         "Co se prodalo (%s)",
+	"Operator":                               "Obsluha",
+	"Shift":                                  "Směna",
+	"Receipts":                               "Účtenky",
+	"Mismatches":                             "Rozdíly",
+	"Rate":                                   "Podíl",
+	"Beer":                                   "Pivo",
+	"Product":                                "Produkt",
+	"Share":                                  "Podíl",
+
+	// Audits.
+	"Beer vs bottles":                  "Pivo vs. lahve",
+	"Receipt sequence":                 "Řada účtenek",
+	"VAT rates":                        "Sazby DPH",
+	"After-hours sales":                "Prodej mimo otevírací dobu",
+	"Receipt %s":                       "Účtenka %s",
+	"Refund %s":                        "Vratka %s",
+	"difference %s":                    "rozdíl %s",
+	"Time: %s":                         "Čas: %s",
+	"Total beer: %s":                   "Pivo celkem: %s",
+	"Total bottles: %s":                "Lahve celkem: %s",
+	"Bottles: %s":                      "Lahve: %s",
+	"Difference: %s":                   "Rozdíl: %s",
+	"Refund of: %s (also a refund)":    "Vratka k: %s (také vratka)",
+	"Refund of: %s (not in this file)": "Vratka k: %s (není v tomto souboru)",
+	"Refunded by: %s":                  "Vráceno účtenkou: %s",
+	"beer refunded without bottles":    "pivo vráceno bez lahví",
+	"bottles refunded without beer":    "lahve vráceny bez piva",
+	"refunded beer and bottles differ": "vrácené pivo a lahve se liší",
+	"refund %s: %s":                    "vratka %s: %s",
+	"Register: %s":                     "Pokladna: %s",
+	"Operator: %s":                     "Obsluha: %s",
+	"Refund of: %s":                    "Vratka k: %s",
+	"Rows:":                            "Řádky:",
+	"Status: %s":                       "Stav: %s",
+	"Resolved: %s":                     "Vyřešeno: %s",
+	"Status: netted into receipt %s":   "Stav: započteno do účtenky %s",
+	"Status: no beer or bottles":       "Stav: bez piva a lahví",
+	"Duplicate: %s":                    "Duplicita: %s",
+	"Findings:":                        "Nálezy:",
+	"suspicious, %s":                   "podezřelé, %s",
+	"OK, within tolerance (%s)":        "OK, v toleranci (%s)",
+	"OK, beer and bottles match":       "OK, pivo a lahve sedí",
+	"mismatch, %s of beer sold without bottles":              "rozdíl, %s piva prodáno bez lahví",
+	"mismatch, %s of bottles sold without beer":              "rozdíl, %s lahví prodáno bez piva",
+	"mismatch, bottles hold %s more than the beer sold":      "rozdíl, do lahví se vejde o %s víc, než kolik piva se prodalo",
+	"mismatch, %s of beer did not fit into the bottles sold": "rozdíl, %s piva se do prodaných lahví nevešlo",
+	"explained":                 "vysvětleno",
+	"staff error":               "chyba obsluhy",
+	"fixed in POS":              "opraveno v pokladně",
+	"Register -":                "Pokladna -",
+	"%d missing":                "chybí %d",
+	"%d-%d missing":             "chybí %d-%d",
+	"row %d: unreadable VAT %q": "řádek %d: nečitelné DPH %q",
+	"row %d: %s / %s charged %s VAT, expected %s": "řádek %d: %s / %s účtováno DPH %s, očekáváno %s",
+	"Alcohol: %s / %s x%s":                        "Alkohol: %s / %s x%s",
+	"%s issued %s after %s":                       "%s vystavena %s po %s",
+	"%s issued at %s and %s (row %d)":             "%s vystavena %s a %s (řádek %d)",
+	"issued at %s and %s (row %d)":                "vystaveno %s a %s (řádek %d)",
+	"%s reused on row %d":                         "%s znovu použita na řádku %d",
+	"reused on row %d":                            "znovu použito na řádku %d",
+	"issued %s, opening hours %s":                 "vystaveno %s, otevírací doba %s",
+
+	// Inventory and stats.
+	"🧴 %s bottles: stock is %d. A delivery was probably not recorded.": "🧴 Lahve %s: zásoba je %d. Nejspíš nebyla zapsána dodávka.",
+	"🧴 %s bottles are running low: %d left (threshold %d).":            "🧴 Lahve %s docházejí: zbývá %d (limit %d).",
+	"No bottle stock recorded. Use /stock <size> <count>.":             "Žádná zásoba lahví. Použijte /stock <velikost> <počet>.",
+	"Bottle stock:\n":                                               "Zásoba lahví:\n",
+	"%s %s: %d (threshold %d)\n":                                    "%s %s: %d (limit %d)\n",
+	"%s bottles: expected %d, counted %d. %d missing.":              "Lahve %s: očekáváno %d, napočítáno %d. Chybí %d.",
+	"%s bottles: expected %d, counted %d. %d more than expected.":   "Lahve %s: očekáváno %d, napočítáno %d. O %d víc, než se čekalo.",
+	"%s bottles: count matches (%d).":                               "Lahve %s: počet sedí (%d).",
+	"🛢️ %s: sold %s from a %s keg. Unrecorded keg or over-pouring?": "🛢️ %s: prodáno %s ze sudu %s. Nezapsaný sud, nebo přelévání?",
+	"🛢️ %s keg is nearly empty: %s of %s left.":                     "🛢️ Sud %s je skoro prázdný: zbývá %s z %s.",
+	"No kegs tapped. Use /tap <product> <liters>l.":                 "Žádný sud není naražený. Použijte /tap <produkt> <litry>l.",
+	"Tapped kegs:\n":                         "Naražené sudy:\n",
+	"%s %s: %s of %s left (tapped %s)\n":     "%s %s: zbývá %s z %s (naraženo %s)\n",
+	"No checked receipts between %s and %s.": "Mezi %s a %s nejsou žádné zkontrolované účtenky.",
+	"Receipts checked: %d\n":                 "Zkontrolováno účtenek: %d\n",
+	"Mismatches: %d (%.1f%%)\n":              "Rozdíly: %d (%.1f %%)\n",
+	"Beer: %.1fL\n":                          "Pivo: %.1f l\n",
+	"Bottles: %s\n":                          "Lahve: %s\n",
+	"\nMismatches per day:\n%s\n%s .. %s\n":  "\nRozdíly po dnech:\n%s\n%s .. %s\n",
+	"\nWorst days:\n":                        "\nNejhorší dny:\n",
+	"%s %s: %d of %d receipts (%.0f%%)\n":    "%s %s: %d z %d účtenek (%.0f %%)\n",
+	"Mon":                                    "po",
+	"Tue":                                    "út",
+	"Wed":                                    "st",
+	"Thu":                                    "čt",
+	"Fri":                                    "pá",
+	"Sat":                                    "so",
+	"Sun":                                    "ne",
+}
+
+var czechPlurals = map[string]Forms{
+	"%d receipts":                                   {One: "%d účtenka", Few: "%d účtenky", Other: "%d účtenek"},
+	"%d mismatches":                                 {One: "%d rozdíl", Few: "%d rozdíly", Other: "%d rozdílů"},
+	"Checked %d receipts.":                          {One: "Zkontrolována %d účtenka.", Few: "Zkontrolovány %d účtenky.", Other: "Zkontrolováno %d účtenek."},
+	"Found %d mismatches.":                          {One: "Nalezen %d rozdíl.", Few: "Nalezeny %d rozdíly.", Other: "Nalezeno %d rozdílů."},
+	"...and %d more products\n":                     {One: "...a %d další produkt\n", Few: "...a %d další produkty\n", Other: "...a %d dalších produktů\n"},
+	"Mismatch rate per operator over %d reports:\n": {One: "Podíl rozdílů podle obsluhy za %d report:\n", Few: "Podíl rozdílů podle obsluhy za %d reporty:\n", Other: "Podíl rozdílů podle obsluhy za %d reportů:\n"},
+	"Nobody has %d receipts yet.\n":                 {One: "Zatím nikdo nemá %d účtenku.\n", Few: "Zatím nikdo nemá %d účtenky.\n", Other: "Zatím nikdo nemá %d účtenek.\n"},
+	"Not ranked, fewer than %d receipts: %s\n":      {One: "Bez pořadí, méně než %d účtenka: %s\n", Few: "Bez pořadí, méně než %d účtenky: %s\n", Other: "Bez pořadí, méně než %d účtenek: %s\n"},
+	"Stats %s - %s (%d reports)\n":                  {One: "Statistika %s - %s (%d report)\n", Few: "Statistika %s - %s (%d reporty)\n", Other: "Statistika %s - %s (%d reportů)\n"},
+}
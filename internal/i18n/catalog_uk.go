@@ -0,0 +1,303 @@
+package i18n
+
+var ukrainianMessages = map[string]string{
+	// Bot replies.
+	"Send me an .xlsx or .csv file and I will process it.":            "Надішліть мені файл .xlsx або .csv, і я його оброблю.",
+	"Unknown command. Use /help.":                                     "Невідома команда. Скористайтеся /help.",
+	"Too many uploads. Try again in %ds.":                             "Забагато файлів. Спробуйте ще раз через %d с.",
+	"Please upload a .xlsx or .csv file.":                             "Завантажте, будь ласка, файл .xlsx або .csv.",
+	"File is too large (%d bytes). Max allowed is %d bytes.":          "Файл завеликий (%d байтів). Максимум — %d байтів.",
+	"Failed to download the file.":                                    "Не вдалося завантажити файл.",
+	"File rejected: %s.":                                              "Файл відхилено: %s.",
+	"Failed to process the file.":                                     "Не вдалося обробити файл.",
+	"Report #%d":                                                      "Звіт №%d",
+	"Report #%d not found.":                                           "Звіт №%d не знайдено.",
+	"This file was already processed on %s (report #%d).":             "Цей файл уже оброблено %s (звіт №%d).",
+	"Show report":                                                     "Показати звіт",
+	"Process again":                                                   "Обробити ще раз",
+	"This upload is no longer available. Please send the file again.": "Цей файл більше не доступний. Надішліть його ще раз.",
+	`Upload an .xlsx or .csv document. I will download and process it.
+
+/audits - list audits for this chat
+/audits on|off <name> - toggle an audit
+/settings - tolerance, snark, tone, time zone, audits, columns and language for this chat
+/snark - phrases of this chat, /snark add <kind> <text> to add one
+/tap <product> [<liters>l] - tap a new keg
+/kegs - keg status
+/stock - bottle stock
+/stock <size> <count|+delivered> [threshold] - set stock
+/count <size> <counted> - reconcile with a physical count
+/export [xlsx|json] [number] - download a report
+/operators [min receipts] - mismatch rate per operator
+/stats [7d|30d|from..to] - trends over stored reports
+/chart [days|hours|heatmap] [7d|30d|from..to|#report] - chart image
+/receipt <number> - look up a receipt in stored reports
+/resolve <number> - mark a mismatch as resolved
+/resolutions - who resolved what and when`: `Завантажте документ .xlsx або .csv. Я його завантажу й оброблю.
+
+/audits - перевірки в цьому чаті
+/audits on|off <назва> - увімкнути або вимкнути перевірку
+/settings - допуск, коментарі, тон, часовий пояс, перевірки, стовпці й мова цього чату
+/snark - фрази цього чату, /snark add <вид> <текст> додає нову
+/tap <продукт> [<літри>l] - підключити нову кегу
+/kegs - стан кег
+/stock - запас пляшок
+/stock <об'єм> <кількість|+доставлено> [поріг] - задати запас
+/count <об'єм> <пораховано> - звірити з фактичним підрахунком
+/export [xlsx|json] [номер] - завантажити звіт
+/operators [мін. чеків] - частка розбіжностей за касирами
+/stats [7d|30d|від..до] - динаміка за збереженими звітами
+/chart [days|hours|heatmap] [7d|30d|від..до|#звіт] - графік
+/receipt <номер> - знайти чек у збережених звітах
+/resolve <номер> - позначити розбіжність як вирішену
+/resolutions - хто що вирішив і коли`,
+
+	// Settings.
+	`Usage:
+/settings - open the settings menu
+/settings tolerance <ml>|default
+/settings snark off|mismatch|always|default
+/settings tone <name>|default
+/settings timezone <Area/City>|default
+/settings columns <profile>|default
+/settings language en|cs|uk|default
+/settings audit <name> - toggle an audit
+/settings reset`: `Використання:
+/settings - відкрити меню налаштувань
+/settings tolerance <мл>|default
+/settings snark off|mismatch|always|default
+/settings tone <назва>|default
+/settings timezone <Регіон/Місто>|default
+/settings columns <профіль>|default
+/settings language en|cs|uk|default
+/settings audit <назва> - увімкнути або вимкнути перевірку
+/settings reset`,
+	"Failed to save the settings.":                "Не вдалося зберегти налаштування.",
+	"Failed to save.":                             "Не вдалося зберегти.",
+	"Unknown option.":                             "Невідомий варіант.",
+	"Tolerance must be a number of milliliters.":  "Допуск має бути кількістю мілілітрів.",
+	"Unknown snark level %s.":                     "Невідомий рівень коментарів %s.",
+	"Unknown time zone %s.":                       "Невідомий часовий пояс %s.",
+	"Unknown column profile %s.":                  "Невідомий профіль стовпців %s.",
+	"Unknown audit %s.":                           "Невідома перевірка %s.",
+	"Unknown language %s.":                        "Невідома мова %s.",
+	"Unknown setting %s.":                         "Невідоме налаштування %s.",
+	"Unknown tone %s.":                            "Невідомий тон %s.",
+	"Tone: %s%s\n":                                "Тон: %s%s\n",
+	"Tone":                                        "Тон",
+	"Tone of the phrases in reports and remarks:": "Тон фраз у звітах і коментарях:",
+
+	// Phrases.
+	`Usage:
+/snark - list the phrases of this chat
+/snark add <kind> <text> - add a phrase
+/snark remove <kind> <number> - remove a phrase
+Kinds: match (opens a clean report), mismatch and snark-mismatch (remark after mismatches), snark-match (remark after a clean report)`: `Використання:
+/snark - фрази цього чату
+/snark add <вид> <текст> - додати фразу
+/snark remove <вид> <номер> - видалити фразу
+Види: match (початок звіту без розбіжностей), mismatch і snark-mismatch (коментар після розбіжностей), snark-match (коментар після звіту без розбіжностей)`,
+	"Unknown phrase kind %s.":                                "Невідомий вид фрази %s.",
+	"Phrases can be at most %d characters long.":             "Фраза може містити щонайбільше %d символів.",
+	"A chat can have at most %d phrases. Remove some first.": "У чаті може бути щонайбільше %d фраз. Спершу видаліть деякі.",
+	"There is no %s phrase number %d.":                       "Фрази %s з номером %d немає.",
+	"Tone: %s (change it with /settings tone)\n":             "Тон: %s (змінити: /settings tone)\n",
+	"This chat has no phrases of its own.":                   "У цього чату немає власних фраз.",
+	" (default)":                                             " (за замовчуванням)",
+	"server time":                                            "час сервера",
+	"from Telegram":                                          "як у Telegram",
+	"Settings for this chat:\n":                              "Налаштування цього чату:\n",
+	"Tolerance: %d ml%s\n":                                   "Допуск: %d мл%s\n",
+	"Snark: %s%s\n":                                          "Коментарі: %s%s\n",
+	"Time zone: %s%s\n":                                      "Часовий пояс: %s%s\n",
+	"Audits: %s%s\n":                                         "Перевірки: %s%s\n",
+	"Columns: %s%s\n":                                        "Стовпці: %s%s\n",
+	"Language: %s%s":                                         "Мова: %s%s",
+	"Tolerance":                                              "Допуск",
+	"Snark":                                                  "Коментарі",
+	"Time zone":                                              "Часовий пояс",
+	"Audits":                                                 "Перевірки",
+	"Columns":                                                "Стовпці",
+	"Language":                                               "Мова",
+	"Reset":                                                  "Скинути",
+	"« Back":                                                 "« Назад",
+	"Default":                                                "За замовчуванням",
+	"Audits run on uploads:":                                 "Перевірки для завантажених файлів:",
+	"Column names of the export:":                            "Назви стовпців експорту:",
+	"Snarky remarks after a report:":                         "Саркастичні коментарі після звіту:",
+	"Beer vs bottles difference still counted as a match:":                              "Різниця між пивом і пляшками, яка ще вважається збігом:",
+	"Time zone for dates and day ranges (other zones: /settings timezone <Area/City>):": "Часовий пояс для дат і діапазонів днів (інші пояси: /settings timezone <Регіон/Місто>):",
+	"Language of replies and reports (default: the language of your Telegram app):":     "Мова відповідей і звітів (за замовчуванням — мова вашого застосунку Telegram):",
+
+	// Commands.
+	"Usage: /audits on|off <name>":                                                "Використання: /audits on|off <назва>",
+	"Unknown audit: %s":                                                           "Невідома перевірка: %s",
+	"Audits for this chat:\n":                                                     "Перевірки цього чату:\n",
+	"Failed to load the report.":                                                  "Не вдалося завантажити звіт.",
+	"Failed to load report history.":                                              "Не вдалося завантажити історію звітів.",
+	"Usage: /chart [days|hours|heatmap] [7d|30d|from..to|#report]":                "Використання: /chart [days|hours|heatmap] [7d|30d|від..до|#звіт]",
+	"No checked receipts to chart.":                                               "Немає перевірених чеків для графіка.",
+	"Usage: /export [xlsx|json] [report number]":                                  "Використання: /export [xlsx|json] [номер звіту]",
+	"Nothing to export. Upload a file first or check the report number.":          "Немає що експортувати. Спершу надішліть файл або перевірте номер звіту.",
+	"Failed to export the report.":                                                "Не вдалося експортувати звіт.",
+	"Usage: /tap <product> [<liters>l]":                                           "Використання: /tap <товар> [<літри>l]",
+	"The keg size for %s is unknown. Use /tap %s <liters>l.":                      "Розмір кеги для %s невідомий. Використайте /tap %s <літри>l.",
+	"Failed to save the keg.":                                                     "Не вдалося зберегти кегу.",
+	"🛢️ Tapped %s (%.1fL).":                                                       "🛢️ Підключено %s (%.1f л).",
+	"\nPrevious keg: %.1fL sold of %.1fL.":                                        "\nПопередня кега: продано %.1f л з %.1f л.",
+	"Failed to load kegs.":                                                        "Не вдалося завантажити кеги.",
+	"Failed to load bottle stock.":                                                "Не вдалося завантажити запас пляшок.",
+	"Usage: /stock <size> <count|+delivered> [threshold], e.g. /stock 0.5 120 20": "Використання: /stock <розмір> <кількість|+доставлено> [поріг], напр. /stock 0.5 120 20",
+	"Failed to save bottle stock.":                                                "Не вдалося зберегти запас пляшок.",
+	"Usage: /count <size> <counted>, e.g. /count 0.5 96":                          "Використання: /count <розмір> <пораховано>, напр. /count 0.5 96",
+	"No stock recorded for %s bottles. Use /stock first.":                         "Для пляшок %s запас не записано. Спершу використайте /stock.",
+	"Failed to save the count.":                                                   "Не вдалося зберегти підрахунок.",
+	"Usage: /operators [minimum receipts]":                                        "Використання: /operators [мінімум чеків]",
+	"No operator data yet. The export needs an operator column (e.g. Pokladník).": "Даних про касирів ще немає. Експорт потребує стовпця з касиром (напр. Pokladník).",
+	"%d. %s: %.1f%% (%d of %d receipts)\n":                                        "%d. %s: %.1f%% (%d з %d чеків)\n",
+	"Usage: /receipt <number>":                                                    "Використання: /receipt <номер>",
+	"Report #%d (%s, %s)\n\n%s":                                                   "Звіт №%d (%s, %s)\n\n%s",
+	"Receipt %s not found in stored reports.":                                     "Чек %s не знайдено в збережених звітах.",
+	"and %d more":                                                "і ще %d",
+	"Also in reports %s":                                         "Також у звітах %s",
+	"%s resolved by %s on %s":                                    "%s вирішив(ла) %s %s",
+	"Usage: /resolve <receipt number>":                           "Використання: /resolve <номер чека>",
+	"Receipt %s has no mismatch to resolve.":                     "Чек %s не має розбіжності для вирішення.",
+	"Receipt %s is too long for buttons.":                        "Чек %s задовгий для кнопок.",
+	"Resolve receipt %s as:":                                     "Вирішити чек %s як:",
+	"Receipt %s is resolved as %s. Change to:":                   "Чек %s вирішено як %s. Змінити на:",
+	"Unknown action.":                                            "Невідома дія.",
+	"Saved.":                                                     "Збережено.",
+	"Receipt %s resolved as %s by %s.":                           "Чек %s вирішено як %s (%s).",
+	"Reply to this message to add a comment to receipt %s.":      "Дайте відповідь на це повідомлення, щоб додати коментар до чека %s.",
+	"Receipt %s is not resolved.":                                "Чек %s не вирішено.",
+	"Failed to save the comment.":                                "Не вдалося зберегти коментар.",
+	"Comment added to receipt %s.":                               "Коментар до чека %s додано.",
+	"Failed to load the resolution log.":                         "Не вдалося завантажити журнал вирішень.",
+	"No mismatches resolved yet. Use /resolve <receipt number>.": "Ще жодну розбіжність не вирішено. Використайте /resolve <номер чека>.",
+	"Resolution log:\n":                                          "Журнал вирішень:\n",
+	"comment on %s: %s\n":                                        "коментар до %s: %s\n",
+	"%s resolved as %s\n":                                        "%s вирішено як %s\n",
+	"Usage: /stats [7d|30d|2026-02-01..2026-02-28]":              "Використання: /stats [7d|30d|2026-02-01..2026-02-28]",
+	"invalid date: %s":                                           "недійсна дата: %s",
+	"range ends before it starts":                                "діапазон закінчується раніше, ніж починається",
+	"range is longer than %d days":                               "діапазон довший за %d днів",
+	"invalid range: %s":                                          "недійсний діапазон: %s",
+
+	// Reports.
+	"No matching beer/PET rows found.":       "Рядків із пивом або ПЕТ-пляшками не знайдено.",
+	"All beer vs bottles match.":             "Пиво й пляшки всюди збігаються.",
+	"Refunds: %d standalone, %d suspicious.": "Повернення: окремих %d, підозрілих %d.",
+	"...truncated":                           "...скорочено",
+	"\n===== Operators =====\n":              "\n===== Касири =====\n",
+	"\n===== Shifts =====\n":                 "\n===== Зміни =====\n",
+	"\n===== What sold (%s) =====\n":         "\n===== Що продано (%s) =====\n",
+	"By category: %s\n":                      "За категоріями: %s\n",
+	"%d more":                                "ще %d",
+	"Operators":                              "Касири",
+	"Shifts":                                 "Зміни",
+	"What sold (%s)":                         "Що продано (%s)",
+	"Operator":                               "Касир",
+	"Shift":                                  "Зміна",
+	"Receipts":                               "Чеки",
+	"Mismatches":                             "Розбіжності",
+	"Rate":                                   "Частка",
+	"Beer":                                   "Пиво",
+	"Product":                                "Товар",
+	"Share":                                  "Частка",
+
+	// Audits.
+	"Beer vs bottles":                  "Пиво проти пляшок",
+	"Receipt sequence":                 "Послідовність чеків",
+	"VAT rates":                        "Ставки ПДВ",
+	"After-hours sales":                "Продаж у неробочий час",
+	"Receipt %s":                       "Чек %s",
+	"Refund %s":                        "Повернення %s",
+	"difference %s":                    "різниця %s",
+	"Time: %s":                         "Час: %s",
+	"Total beer: %s":                   "Пиво разом: %s",
+	"Total bottles: %s":                "Пляшки разом: %s",
+	"Bottles: %s":                      "Пляшки: %s",
+	"Difference: %s":                   "Різниця: %s",
+	"Refund of: %s (also a refund)":    "Повернення до: %s (теж повернення)",
+	"Refund of: %s (not in this file)": "Повернення до: %s (немає в цьому файлі)",
+	"Refunded by: %s":                  "Повернено чеком: %s",
+	"beer refunded without bottles":    "пиво повернено без пляшок",
+	"bottles refunded without beer":    "пляшки повернено без пива",
+	"refunded beer and bottles differ": "повернене пиво й пляшки не збігаються",
+	"refund %s: %s":                    "повернення %s: %s",
+	"Register: %s":                     "Каса: %s",
+	"Operator: %s":                     "Касир: %s",
+	"Refund of: %s":                    "Повернення до: %s",
+	"Rows:":                            "Рядки:",
+	"Status: %s":                       "Стан: %s",
+	"Resolved: %s":                     "Вирішено: %s",
+	"Status: netted into receipt %s":   "Стан: зараховано до чека %s",
+	"Status: no beer or bottles":       "Стан: без пива й пляшок",
+	"Duplicate: %s":                    "Дублікат: %s",
+	"Findings:":                        "Знахідки:",
+	"suspicious, %s":                   "підозріло, %s",
+	"OK, within tolerance (%s)":        "OK, у межах допуску (%s)",
+	"OK, beer and bottles match":       "OK, пиво й пляшки збігаються",
+	"mismatch, %s of beer sold without bottles":              "розбіжність, %s пива продано без пляшок",
+	"mismatch, %s of bottles sold without beer":              "розбіжність, %s пляшок продано без пива",
+	"mismatch, bottles hold %s more than the beer sold":      "розбіжність, пляшки вміщують на %s більше, ніж продано пива",
+	"mismatch, %s of beer did not fit into the bottles sold": "розбіжність, %s пива не вмістилося в продані пляшки",
+	"explained":                 "пояснено",
+	"staff error":               "помилка персоналу",
+	"fixed in POS":              "виправлено в касі",
+	"Register -":                "Каса -",
+	"%d missing":                "бракує %d",
+	"%d-%d missing":             "бракує %d-%d",
+	"row %d: unreadable VAT %q": "рядок %d: нечитабельний ПДВ %q",
+	"row %d: %s / %s charged %s VAT, expected %s": "рядок %d: %s / %s нараховано ПДВ %s, очікувалося %s",
+	"Alcohol: %s / %s x%s":                        "Алкоголь: %s / %s x%s",
+	"%s issued %s after %s":                       "%s видано %s після %s",
+	"%s issued at %s and %s (row %d)":             "%s видано %s і %s (рядок %d)",
+	"issued at %s and %s (row %d)":                "видано %s і %s (рядок %d)",
+	"%s reused on row %d":                         "%s повторно використано в рядку %d",
+	"reused on row %d":                            "повторно використано в рядку %d",
+	"issued %s, opening hours %s":                 "видано %s, години роботи %s",
+
+	// Inventory and stats.
+	"🧴 %s bottles: stock is %d. A delivery was probably not recorded.": "🧴 Пляшки %s: запас %d. Мабуть, не записали доставку.",
+	"🧴 %s bottles are running low: %d left (threshold %d).":            "🧴 Пляшки %s закінчуються: залишилося %d (поріг %d).",
+	"No bottle stock recorded. Use /stock <size> <count>.":             "Запас пляшок не записано. Використайте /stock <розмір> <кількість>.",
+	"Bottle stock:\n":                                               "Запас пляшок:\n",
+	"%s %s: %d (threshold %d)\n":                                    "%s %s: %d (поріг %d)\n",
+	"%s bottles: expected %d, counted %d. %d missing.":              "Пляшки %s: очікувалося %d, пораховано %d. Бракує %d.",
+	"%s bottles: expected %d, counted %d. %d more than expected.":   "Пляшки %s: очікувалося %d, пораховано %d. На %d більше, ніж очікувалося.",
+	"%s bottles: count matches (%d).":                               "Пляшки %s: кількість збігається (%d).",
+	"🛢️ %s: sold %s from a %s keg. Unrecorded keg or over-pouring?": "🛢️ %s: продано %s з кеги %s. Незаписана кега чи переливання?",
+	"🛢️ %s keg is nearly empty: %s of %s left.":                     "🛢️ Кега %s майже порожня: залишилося %s з %s.",
+	"No kegs tapped. Use /tap <product> <liters>l.":                 "Жодну кегу не підключено. Використайте /tap <товар> <літри>l.",
+	"Tapped kegs:\n":                         "Підключені кеги:\n",
+	"%s %s: %s of %s left (tapped %s)\n":     "%s %s: залишилося %s з %s (підключено %s)\n",
+	"No checked receipts between %s and %s.": "Між %s і %s немає перевірених чеків.",
+	"Receipts checked: %d\n":                 "Перевірено чеків: %d\n",
+	"Mismatches: %d (%.1f%%)\n":              "Розбіжності: %d (%.1f%%)\n",
+	"Beer: %.1fL\n":                          "Пиво: %.1f л\n",
+	"Bottles: %s\n":                          "Пляшки: %s\n",
+	"\nMismatches per day:\n%s\n%s .. %s\n":  "\nРозбіжності за днями:\n%s\n%s .. %s\n",
+	"\nWorst days:\n":                        "\nНайгірші дні:\n",
+	"%s %s: %d of %d receipts (%.0f%%)\n":    "%s %s: %d з %d чеків (%.0f%%)\n",
+	"Mon":                                    "пн",
+	"Tue":                                    "вт",
+	"Wed":                                    "ср",
+	"Thu":                                    "чт",
+	"Fri":                                    "пт",
+	"Sat":                                    "сб",
+	"Sun":                                    "нд",
+}
+
+var ukrainianPlurals = map[string]Forms{
+	"%d receipts":                                   {One: "%d чек", Few: "%d чеки", Many: "%d чеків"},
+	"%d mismatches":                                 {One: "%d розбіжність", Few: "%d розбіжності", Many: "%d розбіжностей"},
+	"Checked %d receipts.":                          {One: "Перевірено %d чек.", Few: "Перевірено %d чеки.", Many: "Перевірено %d чеків."},
+	"Found %d mismatches.":                          {One: "Знайдено %d розбіжність.", Few: "Знайдено %d розбіжності.", Many: "Знайдено %d розбіжностей."},
+	"...and %d more products\n":                     {One: "...і ще %d товар\n", Few: "...і ще %d товари\n", Many: "...і ще %d товарів\n"},
+	"Mismatch rate per operator over %d reports:\n": {One: "Частка розбіжностей за касирами за %d звіт:\n", Few: "Частка розбіжностей за касирами за %d звіти:\n", Many: "Частка розбіжностей за касирами за %d звітів:\n"},
+	"Nobody has %d receipts yet.\n":                 {One: "Ще ніхто не має %d чека.\n", Few: "Ще ніхто не має %d чеків.\n", Many: "Ще ніхто не має %d чеків.\n"},
+	"Not ranked, fewer than %d receipts: %s\n":      {One: "Без рейтингу, менше ніж %d чек: %s\n", Few: "Без рейтингу, менше ніж %d чеки: %s\n", Many: "Без рейтингу, менше ніж %d чеків: %s\n"},
+	"Stats %s - %s (%d reports)\n":                  {One: "Статистика %s - %s (%d звіт)\n", Few: "Статистика %s - %s (%d звіти)\n", Many: "Статистика %s - %s (%d звітів)\n"},
+}
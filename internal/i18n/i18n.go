@@ -0,0 +1,171 @@
+// Package i18n translates user-facing text. Messages are looked up by their
+// English format string, so code keeps reading like plain fmt calls and a
+// missing translation falls back to English.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported languages.
+const (
+	English   = "en"
+	Czech     = "cs"
+	Ukrainian = "uk"
+)
+
+// Languages lists the supported languages in the order they are offered.
+var Languages = []string{English, Czech, Ukrainian}
+
+// Forms are the plural forms of a message. Which forms a language uses
+// depends on its plural rule; a missing form falls back to Other.
+type Forms struct {
+	One   string
+	Few   string
+	Many  string
+	Other string
+}
+
+type catalog struct {
+	name     string
+	messages map[string]string
+	plurals  map[string]Forms
+	rule     func(n int) pluralCategory
+}
+
+var catalogs = map[string]catalog{
+	English:   {name: "English", rule: englishRule},
+	Czech:     {name: "Čeština", messages: czechMessages, plurals: czechPlurals, rule: czechRule},
+	Ukrainian: {name: "Українська", messages: ukrainianMessages, plurals: ukrainianPlurals, rule: ukrainianRule},
+}
+
+// Name returns the name of lang in that language.
+func Name(lang string) string {
+	if c, ok := catalogs[lang]; ok {
+		return c.name
+	}
+	return lang
+}
+
+// Match maps a Telegram language_code such as "cs" or "uk-UA" to a supported
+// language. It returns "" when the language is not supported.
+func Match(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if base, _, ok := strings.Cut(code, "-"); ok {
+		code = base
+	}
+	if _, ok := catalogs[code]; ok {
+		return code
+	}
+	return ""
+}
+
+// Printer formats messages in one language.
+type Printer struct {
+	lang string
+}
+
+// For returns a printer for lang. Unsupported languages print English.
+func For(lang string) Printer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = English
+	}
+	return Printer{lang: lang}
+}
+
+// Lang returns the language of the printer.
+func (p Printer) Lang() string {
+	if p.lang == "" {
+		return English
+	}
+	return p.lang
+}
+
+// Sprintf translates format and formats it like fmt.Sprintf.
+func (p Printer) Sprintf(format string, args ...any) string {
+	if translated, ok := catalogs[p.Lang()].messages[format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Text translates a message that takes no arguments. Unlike Sprintf, it
+// never treats key as a format.
+func (p Printer) Text(key string) string {
+	if translated, ok := catalogs[p.Lang()].messages[key]; ok {
+		return translated
+	}
+	return key
+}
+
+// Plural picks the form of a message for n and formats it with args. one
+// and other are the English forms; other is the catalog key.
+func (p Printer) Plural(n int, one, other string, args ...any) string {
+	c := catalogs[p.Lang()]
+	forms, ok := c.plurals[other]
+	if !ok {
+		forms = Forms{One: one, Other: other}
+		c.rule = englishRule
+	}
+
+	format := forms.Other
+	switch c.rule(n) {
+	case pluralOne:
+		format = forms.One
+	case pluralFew:
+		format = forms.Few
+	case pluralMany:
+		format = forms.Many
+	}
+	if format == "" {
+		format = forms.Other
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+type pluralCategory int
+
+const (
+	pluralOther pluralCategory = iota
+	pluralOne
+	pluralFew
+	pluralMany
+)
+
+func englishRule(n int) pluralCategory {
+	if n == 1 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+// czechRule: 1 účtenka, 2-4 účtenky, 0 and 5+ účtenek.
+func czechRule(n int) pluralCategory {
+	switch {
+	case n == 1:
+		return pluralOne
+	case n >= 2 && n <= 4:
+		return pluralFew
+	default:
+		return pluralOther
+	}
+}
+
+// ukrainianRule: 1, 21, 31 чек; 2-4, 22-24 чеки; 0, 5-20, 25-30 чеків.
+func ukrainianRule(n int) pluralCategory {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return pluralOne
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return pluralFew
+	default:
+		return pluralMany
+	}
+}
@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestPlural(t *testing.T) {
+	cases := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{English, 1, "1 receipt"},
+		{English, 0, "0 receipts"},
+		{English, 5, "5 receipts"},
+		{Czech, 1, "1 účtenka"},
+		{Czech, 3, "3 účtenky"},
+		{Czech, 5, "5 účtenek"},
+		{Czech, 0, "0 účtenek"},
+		{Ukrainian, 1, "1 чек"},
+		{Ukrainian, 21, "21 чек"},
+		{Ukrainian, 11, "11 чеків"},
+		{Ukrainian, 22, "22 чеки"},
+		{Ukrainian, 12, "12 чеків"},
+		{Ukrainian, 5, "5 чеків"},
+	}
+	for _, c := range cases {
+		if got := For(c.lang).Plural(c.n, "%d receipt", "%d receipts", c.n); got != c.want {
+			t.Fatalf("%s %d: expected %q, got %q", c.lang, c.n, c.want, got)
+		}
+	}
+
+	if got := For(Czech).Plural(2, "%d cat", "%d cats", 2); got != "2 cats" {
+		t.Fatalf("expected the English fallback, got %q", got)
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	if got := For(Czech).Sprintf("Report #%d", 7); got != "Report č. 7" {
+		t.Fatalf("unexpected translation: %q", got)
+	}
+	if got := For(Ukrainian).Sprintf("untranslated %s", "text"); got != "untranslated text" {
+		t.Fatalf("expected the English fallback, got %q", got)
+	}
+	if got := For("de").Sprintf("Report #%d", 7); got != "Report #7" {
+		t.Fatalf("expected English for an unsupported language, got %q", got)
+	}
+}
+
+func TestText(t *testing.T) {
+	if got := For(Czech).Text("Report #%d"); got != "Report č. %d" {
+		t.Fatalf("unexpected translation: %q", got)
+	}
+	if got := For(English).Text("100% sure"); got != "100% sure" {
+		t.Fatalf("expected the text unformatted, got %q", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for code, want := range map[string]string{
+		"cs":    Czech,
+		"uk-UA": Ukrainian,
+		"EN":    English,
+		"de":    "",
+		"":      "",
+	} {
+		if got := Match(code); got != want {
+			t.Fatalf("%q: expected %q, got %q", code, want, got)
+		}
+	}
+}
+
+var verb = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+// TestCatalogs checks that the translations cover the same messages and keep
+// the verbs of the English text, so arguments land in the right place.
+func TestCatalogs(t *testing.T) {
+	keys := func(m map[string]string) []string {
+		var out []string
+		for k := range m {
+			out = append(out, k)
+		}
+		slices.Sort(out)
+		return out
+	}
+	if !slices.Equal(keys(czechMessages), keys(ukrainianMessages)) {
+		t.Fatalf("Czech and Ukrainian catalogs cover different messages")
+	}
+	for lang, c := range catalogs {
+		for msgid, translated := range c.messages {
+			if !slices.Equal(verb.FindAllString(msgid, -1), verb.FindAllString(translated, -1)) {
+				t.Fatalf("%s: verbs of %q differ in %q", lang, msgid, translated)
+			}
+		}
+		for msgid, forms := range c.plurals {
+			for _, form := range []string{forms.One, forms.Few, forms.Many, forms.Other} {
+				if form != "" && !slices.Equal(verb.FindAllString(msgid, -1), verb.FindAllString(form, -1)) {
+					t.Fatalf("%s: verbs of %q differ in %q", lang, msgid, form)
+				}
+			}
+		}
+	}
+	for msgid := range czechPlurals {
+		if _, ok := ukrainianPlurals[msgid]; !ok {
+			t.Fatalf("no Ukrainian plural for %q", msgid)
+		}
+	}
+}
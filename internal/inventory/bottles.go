@@ -0,0 +1,178 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+// ErrNoStock is returned by CountBottles for a size without recorded stock.
+var ErrNoStock = errors.New("no stock recorded")
+
+// BottleStock is the expected number of empty PET bottles of one size.
+type BottleStock struct {
+	Count     int64     `json:"count"`
+	Threshold int64     `json:"threshold,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BottleCount records a physical count and how far it was from the expected stock.
+type BottleCount struct {
+	SizeML   int64     `json:"size_ml"`
+	Expected int64     `json:"expected"`
+	Counted  int64     `json:"counted"`
+	At       time.Time `json:"at"`
+}
+
+// Shrinkage is the number of bottles missing compared to the expected stock.
+func (c BottleCount) Shrinkage() int64 {
+	return c.Expected - c.Counted
+}
+
+type BottleLevel struct {
+	SizeML    int64
+	Count     int64
+	Threshold int64
+}
+
+func (l BottleLevel) Low() bool {
+	return l.Count < l.Threshold
+}
+
+// SetBottles sets the stock of a size. With add the count is added to the current stock.
+// A threshold of zero keeps the current one.
+func (s *Store) SetBottles(chatID, sizeML, count, threshold int64, add bool, at time.Time) (BottleLevel, error) {
+	if sizeML <= 0 {
+		return BottleLevel{}, fmt.Errorf("invalid bottle size")
+	}
+	if threshold < 0 {
+		return BottleLevel{}, fmt.Errorf("invalid threshold")
+	}
+
+	var level BottleLevel
+	err := s.update(chatID, func(st *chatState) error {
+		stock := st.Bottles[sizeML]
+		if stock == nil {
+			stock = &BottleStock{}
+			st.Bottles[sizeML] = stock
+		}
+		if add {
+			stock.Count += count
+		} else {
+			stock.Count = count
+		}
+		if threshold > 0 {
+			stock.Threshold = threshold
+		}
+		stock.UpdatedAt = at
+		level = s.level(sizeML, stock)
+		return nil
+	})
+	return level, err
+}
+
+// CountBottles reconciles the stock of a size with a physical count.
+func (s *Store) CountBottles(chatID, sizeML, counted int64, at time.Time) (BottleCount, error) {
+	if counted < 0 {
+		return BottleCount{}, fmt.Errorf("count must not be negative")
+	}
+
+	var result BottleCount
+	err := s.update(chatID, func(st *chatState) error {
+		stock := st.Bottles[sizeML]
+		if stock == nil {
+			return fmt.Errorf("%w for %s bottles", ErrNoStock, formatLiters(sizeML))
+		}
+		result = BottleCount{SizeML: sizeML, Expected: stock.Count, Counted: counted, At: at}
+		st.BottleCounts = append(st.BottleCounts, result)
+		stock.Count = counted
+		stock.UpdatedAt = at
+		return nil
+	})
+	return result, err
+}
+
+// Bottles returns the stock levels of a chat ordered by size.
+func (s *Store) Bottles(chatID int64) ([]BottleLevel, error) {
+	var levels []BottleLevel
+	err := s.view(chatID, func(st *chatState) {
+		for sizeML, stock := range st.Bottles {
+			levels = append(levels, s.level(sizeML, stock))
+		}
+	})
+	sort.Slice(levels, func(i, j int) bool { return levels[i].SizeML < levels[j].SizeML })
+	return levels, err
+}
+
+// SellBottles takes sold bottles out of stock. Sizes without recorded stock
+// are not tracked. Levels that dropped below their threshold are returned.
+func (s *Store) SellBottles(chatID int64, sold map[int64]int64) ([]BottleLevel, error) {
+	var low []BottleLevel
+	err := s.update(chatID, func(st *chatState) error {
+		for sizeML, count := range sold {
+			stock := st.Bottles[sizeML]
+			if stock == nil || count == 0 {
+				continue
+			}
+			wasLow := s.level(sizeML, stock).Low()
+			stock.Count -= count
+			if level := s.level(sizeML, stock); level.Low() && !wasLow {
+				low = append(low, level)
+			}
+		}
+		return nil
+	})
+	sort.Slice(low, func(i, j int) bool { return low[i].SizeML < low[j].SizeML })
+	return low, err
+}
+
+func (s *Store) level(sizeML int64, stock *BottleStock) BottleLevel {
+	threshold := stock.Threshold
+	if threshold == 0 {
+		threshold = s.lowStock
+	}
+	return BottleLevel{SizeML: sizeML, Count: stock.Count, Threshold: threshold}
+}
+
+// FormatAlert renders the low stock alert of the level for a chat.
+func (l BottleLevel) FormatAlert(p i18n.Printer) string {
+	if l.Count < 0 {
+		return p.Sprintf("🧴 %s bottles: stock is %d. A delivery was probably not recorded.", formatLiters(l.SizeML), l.Count)
+	}
+	return p.Sprintf("🧴 %s bottles are running low: %d left (threshold %d).", formatLiters(l.SizeML), l.Count, l.Threshold)
+}
+
+// FormatBottles renders the stock levels for the /stock command.
+func FormatBottles(levels []BottleLevel, p i18n.Printer) string {
+	if len(levels) == 0 {
+		return p.Sprintf("No bottle stock recorded. Use /stock <size> <count>.")
+	}
+
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Bottle stock:\n"))
+	for _, level := range levels {
+		mark := "🟢"
+		if level.Low() {
+			mark = "🟡"
+		}
+		b.WriteString(p.Sprintf("%s %s: %d (threshold %d)\n", mark, formatLiters(level.SizeML), level.Count, level.Threshold))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Format renders the result of the count for a chat.
+func (c BottleCount) Format(p i18n.Printer) string {
+	shrinkage := c.Shrinkage()
+	switch {
+	case shrinkage > 0:
+		return p.Sprintf("%s bottles: expected %d, counted %d. %d missing.", formatLiters(c.SizeML), c.Expected, c.Counted, shrinkage)
+	case shrinkage < 0:
+		return p.Sprintf("%s bottles: expected %d, counted %d. %d more than expected.", formatLiters(c.SizeML), c.Expected, c.Counted, -shrinkage)
+	default:
+		return p.Sprintf("%s bottles: count matches (%d).", formatLiters(c.SizeML), c.Counted)
+	}
+}
@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_BottleStock(t *testing.T) {
+	store := NewStore(t.TempDir(), 10)
+	now := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+
+	if _, err := store.SetBottles(1, 500, 30, 0, false, now); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := store.SetBottles(1, 1500, 12, 5, false, now); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if level, err := store.SetBottles(1, 500, 5, 0, true, now); err != nil || level.Count != 35 {
+		t.Fatalf("expected 35 after delivery, got: %+v (%v)", level, err)
+	}
+
+	low, err := store.SellBottles(1, map[int64]int64{500: 26, 1500: 2, 2000: 4})
+	if err != nil {
+		t.Fatalf("sell: %v", err)
+	}
+	if len(low) != 1 || low[0].SizeML != 500 || low[0].Count != 9 || low[0].Threshold != 10 {
+		t.Fatalf("expected 0.5L low alert, got: %+v", low)
+	}
+
+	low, err = store.SellBottles(1, map[int64]int64{500: 1})
+	if err != nil || len(low) != 0 {
+		t.Fatalf("expected no repeated alert, got: %+v (%v)", low, err)
+	}
+
+	count, err := store.CountBottles(1, 500, 5, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count.Expected != 8 || count.Shrinkage() != 3 {
+		t.Fatalf("unexpected count: %+v", count)
+	}
+	if _, err := store.CountBottles(1, 2000, 5, now); err == nil {
+		t.Fatal("expected error for untracked size")
+	}
+
+	levels, err := store.Bottles(1)
+	if err != nil {
+		t.Fatalf("bottles: %v", err)
+	}
+	if len(levels) != 2 || levels[0].Count != 5 || levels[1].Count != 10 || levels[1].Threshold != 5 {
+		t.Fatalf("unexpected levels: %+v", levels)
+	}
+}
@@ -0,0 +1,215 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+// ErrUnknownKegSize is returned by Tap when no size is given and none was
+// used for the product before.
+var ErrUnknownKegSize = errors.New("keg size is unknown")
+
+// nearlyEmptyShare is the remaining share of a keg below which it is reported as nearly empty.
+const nearlyEmptyShare = 0.1
+
+// maxKegReports is how many booked reports a keg remembers. Uploads are
+// deduplicated by the report history first, so only recent ones matter.
+const maxKegReports = 50
+
+type Keg struct {
+	Product  string    `json:"product"`
+	SizeML   int64     `json:"size_ml"`
+	SoldML   int64     `json:"sold_ml"`
+	TappedAt time.Time `json:"tapped_at"`
+	// Reports are the latest reports whose sales were booked against the keg.
+	Reports []string `json:"reports,omitempty"`
+}
+
+func (k Keg) RemainingML() int64 {
+	return k.SizeML - k.SoldML
+}
+
+func (k Keg) status() kegStatus {
+	switch {
+	case k.SoldML > k.SizeML:
+		return kegOversold
+	case float64(k.RemainingML()) <= float64(k.SizeML)*nearlyEmptyShare:
+		return kegLow
+	default:
+		return kegOK
+	}
+}
+
+type kegStatus int
+
+const (
+	kegOK kegStatus = iota
+	kegLow
+	kegOversold
+)
+
+// Sale is the beer volume sold of one product, as read from a report.
+// Issued is when the receipt was issued, zero when the file has no time.
+type Sale struct {
+	Category string
+	Product  string
+	ML       int64
+	Issued   time.Time
+}
+
+// Alert is raised when a keg crosses into the nearly empty or oversold state.
+type Alert struct {
+	Keg      Keg
+	Oversold bool
+}
+
+// Format renders the alert for a chat.
+func (a Alert) Format(p i18n.Printer) string {
+	if a.Oversold {
+		return p.Sprintf("🛢️ %s: sold %s from a %s keg. Unrecorded keg or over-pouring?",
+			a.Keg.Product, formatLiters(a.Keg.SoldML), formatLiters(a.Keg.SizeML))
+	}
+	return p.Sprintf("🛢️ %s keg is nearly empty: %s of %s left.",
+		a.Keg.Product, formatLiters(a.Keg.RemainingML()), formatLiters(a.Keg.SizeML))
+}
+
+// Tap starts a new keg of product. When sizeML is zero the size of the last
+// keg tapped for the product is reused. The replaced keg, if any, is returned.
+func (s *Store) Tap(chatID int64, product string, sizeML int64, at time.Time) (Keg, *Keg, error) {
+	product = strings.TrimSpace(product)
+	if product == "" {
+		return Keg{}, nil, fmt.Errorf("product is empty")
+	}
+
+	var tapped Keg
+	var replaced *Keg
+	err := s.update(chatID, func(st *chatState) error {
+		key := productKey(product)
+		if sizeML <= 0 {
+			sizeML = st.KegSizes[key]
+		}
+		if sizeML <= 0 {
+			return fmt.Errorf("%w: %s", ErrUnknownKegSize, product)
+		}
+		st.KegSizes[key] = sizeML
+
+		tapped = Keg{Product: product, SizeML: sizeML, TappedAt: at}
+		for i, keg := range st.Kegs {
+			if productKey(keg.Product) == key {
+				old := keg
+				replaced = &old
+				st.Kegs[i] = tapped
+				return nil
+			}
+		}
+		st.Kegs = append(st.Kegs, tapped)
+		return nil
+	})
+	return tapped, replaced, err
+}
+
+// Kegs returns the currently tapped kegs of a chat.
+func (s *Store) Kegs(chatID int64) ([]Keg, error) {
+	var kegs []Keg
+	err := s.view(chatID, func(st *chatState) {
+		kegs = append(kegs, st.Kegs...)
+	})
+	return kegs, err
+}
+
+// Deplete books the sales of a report against the tapped kegs. A sale goes
+// to the keg named after its product, or failing that after its category.
+// Sales without a keg or issued before the keg was tapped are ignored, and a
+// report already booked against a keg is not booked again. Alerts are
+// returned for kegs whose state got worse.
+func (s *Store) Deplete(chatID int64, report string, sales []Sale) ([]Alert, error) {
+	var alerts []Alert
+	err := s.update(chatID, func(st *chatState) error {
+		before := make([]kegStatus, len(st.Kegs))
+		booked := make([]bool, len(st.Kegs))
+		sold := make([]bool, len(st.Kegs))
+		for i, keg := range st.Kegs {
+			before[i] = keg.status()
+			booked[i] = report != "" && slices.Contains(keg.Reports, report)
+		}
+
+		for _, sale := range sales {
+			i := findKeg(st.Kegs, sale.Product)
+			if i < 0 {
+				i = findKeg(st.Kegs, sale.Category)
+			}
+			if i < 0 || booked[i] {
+				continue
+			}
+			if !sale.Issued.IsZero() && sale.Issued.Before(st.Kegs[i].TappedAt) {
+				continue
+			}
+			st.Kegs[i].SoldML += sale.ML
+			sold[i] = true
+		}
+
+		for i := range st.Kegs {
+			if report != "" && sold[i] {
+				reports := append(st.Kegs[i].Reports, report)
+				st.Kegs[i].Reports = reports[max(len(reports)-maxKegReports, 0):]
+			}
+		}
+
+		for i, keg := range st.Kegs {
+			after := keg.status()
+			if after > before[i] {
+				alerts = append(alerts, Alert{Keg: keg, Oversold: after == kegOversold})
+			}
+		}
+		return nil
+	})
+	return alerts, err
+}
+
+func findKeg(kegs []Keg, name string) int {
+	key := productKey(name)
+	if key == "" {
+		return -1
+	}
+	for i, keg := range kegs {
+		if productKey(keg.Product) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func productKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func formatLiters(ml int64) string {
+	return fmt.Sprintf("%.1fL", float64(ml)/1000.0)
+}
+
+// FormatKegs renders the keg list for the /kegs command.
+func FormatKegs(kegs []Keg, p i18n.Printer) string {
+	if len(kegs) == 0 {
+		return p.Sprintf("No kegs tapped. Use /tap <product> <liters>l.")
+	}
+
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Tapped kegs:\n"))
+	for _, keg := range kegs {
+		mark := "🟢"
+		switch keg.status() {
+		case kegLow:
+			mark = "🟡"
+		case kegOversold:
+			mark = "🔴"
+		}
+		b.WriteString(p.Sprintf("%s %s: %s of %s left (tapped %s)\n",
+			mark, keg.Product, formatLiters(keg.RemainingML()), formatLiters(keg.SizeML), keg.TappedAt.Format("02.01. 15:04")))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStore_TapAndDeplete(t *testing.T) {
+	dataDir := t.TempDir()
+	store := NewStore(dataDir, 0)
+	now := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+
+	if _, _, err := store.Tap(1, "Pivovar Raven", 0, now); err == nil {
+		t.Fatal("expected error when keg size is unknown")
+	}
+	if _, _, err := store.Tap(1, "Pivovar Raven", 30_000, now); err != nil {
+		t.Fatalf("tap: %v", err)
+	}
+	if _, _, err := store.Tap(1, "Kozel 11", 50_000, now); err != nil {
+		t.Fatalf("tap: %v", err)
+	}
+
+	alerts, err := store.Deplete(1, "", []Sale{
+		{Category: "Pivovar Raven", Product: "IPA", ML: 27_500},
+		{Category: "Pivo na čepu", Product: "kozel  11", ML: 10_000},
+		{Category: "Pivovar Unknown", Product: "Lager", ML: 5_000},
+	})
+	if err != nil {
+		t.Fatalf("deplete: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Oversold || alerts[0].Keg.Product != "Pivovar Raven" {
+		t.Fatalf("expected nearly empty alert for Raven, got: %+v", alerts)
+	}
+
+	alerts, err = store.Deplete(1, "", []Sale{{Category: "Pivovar Raven", Product: "IPA", ML: 1_000}})
+	if err != nil {
+		t.Fatalf("deplete: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no repeated alert, got: %+v", alerts)
+	}
+
+	alerts, err = store.Deplete(1, "", []Sale{{Category: "Pivovar Raven", Product: "IPA", ML: 2_000}})
+	if err != nil {
+		t.Fatalf("deplete: %v", err)
+	}
+	if len(alerts) != 1 || !alerts[0].Oversold {
+		t.Fatalf("expected oversold alert, got: %+v", alerts)
+	}
+
+	tapped, replaced, err := store.Tap(1, "pivovar raven", 0, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("re-tap: %v", err)
+	}
+	if tapped.SizeML != 30_000 || replaced == nil || replaced.SoldML != 30_500 {
+		t.Fatalf("unexpected re-tap result: %+v, replaced %+v", tapped, replaced)
+	}
+
+	kegs, err := NewStore(dataDir, 0).Kegs(1)
+	if err != nil {
+		t.Fatalf("kegs: %v", err)
+	}
+	if len(kegs) != 2 || kegs[0].SoldML != 0 || kegs[1].SoldML != 10_000 {
+		t.Fatalf("unexpected persisted kegs: %+v", kegs)
+	}
+}
+
+func TestStore_DepleteOncePerReport(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+	tapped := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	if _, _, err := store.Tap(1, "Kozel", 50_000, tapped); err != nil {
+		t.Fatalf("tap: %v", err)
+	}
+
+	sales := []Sale{
+		{Category: "Pivo na čepu", Product: "Kozel", ML: 4_000, Issued: tapped.Add(-time.Hour)},
+		{Category: "Pivo na čepu", Product: "Kozel", ML: 1_000, Issued: tapped.Add(time.Hour)},
+		{Category: "Pivo na čepu", Product: "Kozel", ML: 500},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := store.Deplete(1, "abc", sales); err != nil {
+			t.Fatalf("deplete: %v", err)
+		}
+	}
+	kegs, err := store.Kegs(1)
+	if err != nil {
+		t.Fatalf("kegs: %v", err)
+	}
+	if kegs[0].SoldML != 1_500 || len(kegs[0].Reports) != 1 {
+		t.Fatalf("expected only sales after the tap, booked once, got: %+v", kegs[0])
+	}
+
+	if _, _, err := store.Tap(1, "Kozel", 0, tapped.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("re-tap: %v", err)
+	}
+	if _, err := store.Deplete(1, "abc", sales); err != nil {
+		t.Fatalf("deplete: %v", err)
+	}
+	if kegs, _ := store.Kegs(1); kegs[0].SoldML != 5_500 {
+		t.Fatalf("expected a new keg to take the report again, got: %+v", kegs[0])
+	}
+}
+
+func TestStore_DepleteRemembersReportsWithSales(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+	tapped := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	for _, product := range []string{"Kozel", "Pilsner"} {
+		if _, _, err := store.Tap(1, product, 50_000, tapped); err != nil {
+			t.Fatalf("tap: %v", err)
+		}
+	}
+
+	sales := []Sale{{Product: "Kozel", ML: 10}}
+	for i := 0; i < maxKegReports+5; i++ {
+		if _, err := store.Deplete(1, fmt.Sprintf("r%d", i), sales); err != nil {
+			t.Fatalf("deplete: %v", err)
+		}
+	}
+	kegs, err := store.Kegs(1)
+	if err != nil {
+		t.Fatalf("kegs: %v", err)
+	}
+	if len(kegs[1].Reports) != 0 {
+		t.Fatalf("expected no reports on the keg without sales, got: %v", kegs[1].Reports)
+	}
+	reports := kegs[0].Reports
+	if len(reports) != maxKegReports || reports[len(reports)-1] != fmt.Sprintf("r%d", maxKegReports+4) {
+		t.Fatalf("expected the latest %d reports, got: %v", maxKegReports, reports)
+	}
+}
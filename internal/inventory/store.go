@@ -0,0 +1,114 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Store keeps the inventory of every chat in its own JSON file under
+// DATA_DIR/inventory. All access goes through a single mutex; the files are
+// small and updated a few times per day.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	lowStock int64
+}
+
+type chatState struct {
+	Kegs         []Keg                  `json:"kegs"`
+	KegSizes     map[string]int64       `json:"keg_sizes"`
+	Bottles      map[int64]*BottleStock `json:"bottles"`
+	BottleCounts []BottleCount          `json:"bottle_counts"`
+}
+
+// NewStore creates a store under dataDir. lowStock is the bottle threshold
+// used for sizes that have no threshold of their own.
+func NewStore(dataDir string, lowStock int64) *Store {
+	return &Store{dir: filepath.Join(dataDir, "inventory"), lowStock: lowStock}
+}
+
+// update loads the chat state, applies fn and writes the state back when fn succeeds.
+func (s *Store) update(chatID int64, fn func(st *chatState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.load(chatID)
+	if err != nil {
+		return err
+	}
+	if err := fn(st); err != nil {
+		return err
+	}
+	return s.save(chatID, st)
+}
+
+func (s *Store) view(chatID int64, fn func(st *chatState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.load(chatID)
+	if err != nil {
+		return err
+	}
+	fn(st)
+	return nil
+}
+
+func (s *Store) path(chatID int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(chatID, 10)+".json")
+}
+
+func (s *Store) load(chatID int64) (*chatState, error) {
+	st := &chatState{}
+	data, err := os.ReadFile(s.path(chatID))
+	if errors.Is(err, os.ErrNotExist) {
+		data = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read inventory: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, fmt.Errorf("decode inventory: %w", err)
+		}
+	}
+	if st.KegSizes == nil {
+		st.KegSizes = make(map[string]int64)
+	}
+	if st.Bottles == nil {
+		st.Bottles = make(map[int64]*BottleStock)
+	}
+	return st, nil
+}
+
+func (s *Store) save(chatID int64, st *chatState) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode inventory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".inventory-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write inventory: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close inventory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(chatID)); err != nil {
+		return fmt.Errorf("rename inventory: %w", err)
+	}
+	return nil
+}
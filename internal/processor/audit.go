@@ -0,0 +1,268 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"bigbrother/internal/i18n"
+)
+
+const (
+	AuditBottles    = "bottles"
+	AuditSequence   = "sequence"
+	AuditVAT        = "vat"
+	AuditAfterHours = "afterhours"
+)
+
+// DefaultAudits are the audits ProcessFile runs when nothing else is asked for.
+var DefaultAudits = []string{AuditBottles, AuditSequence, AuditVAT}
+
+var defaultRegistry = DefaultRegistry()
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// MarshalJSON writes the severity by name, as the XLSX export does.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON reads a severity name.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("decode severity: %w", err)
+	}
+	switch name {
+	case "critical":
+		*s = SeverityCritical
+	case "warning":
+		*s = SeverityWarning
+	case "info":
+		*s = SeverityInfo
+	default:
+		return fmt.Errorf("unknown severity %q", name)
+	}
+	return nil
+}
+
+// Finding is a single problem reported by an audit.
+type Finding struct {
+	Audit     string   `json:"audit"`
+	Severity  Severity `json:"severity"`
+	Subject   string   `json:"subject,omitempty"`
+	ReceiptNo string   `json:"receipt_no,omitempty"`
+	Register  string   `json:"register,omitempty"`
+	Message   string   `json:"message"`
+	Details   []string `json:"details,omitempty"`
+}
+
+// Audit inspects parsed receipts and reports findings. Name is the stable
+// identifier used in settings and commands, Title is shown in reports and
+// translated when it is. Findings are written with p.
+type Audit interface {
+	Name() string
+	Title() string
+	Run(receipts []Receipt, p i18n.Printer) []Finding
+}
+
+// AuditResult holds the findings of one audit that ran on a report.
+type AuditResult struct {
+	Name     string    `json:"name"`
+	Title    string    `json:"title"`
+	Findings []Finding `json:"findings"`
+}
+
+// Registry keeps the audits the bot knows about in registration order.
+type Registry struct {
+	audits []Audit
+	byName map[string]Audit
+}
+
+func NewRegistry(audits ...Audit) *Registry {
+	r := &Registry{byName: make(map[string]Audit)}
+	for _, a := range audits {
+		if err := r.Register(a); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// DefaultRegistry returns a registry with the built-in audits that need no configuration.
+func DefaultRegistry() *Registry {
+	return NewRegistry(NewBottlesAudit(), NewSequenceAudit(), NewVATAudit(DefaultVATRules()))
+}
+
+func (r *Registry) Register(a Audit) error {
+	name := a.Name()
+	if name == "" {
+		return fmt.Errorf("audit name is empty")
+	}
+	if _, ok := r.byName[name]; ok {
+		return fmt.Errorf("audit %q already registered", name)
+	}
+	r.audits = append(r.audits, a)
+	r.byName[name] = a
+	return nil
+}
+
+func (r *Registry) Lookup(name string) (Audit, bool) {
+	a, ok := r.byName[strings.ToLower(strings.TrimSpace(name))]
+	return a, ok
+}
+
+func (r *Registry) Audits() []Audit {
+	return append([]Audit(nil), r.audits...)
+}
+
+// Run executes the named audits in registration order. Unknown names are ignored.
+func (r *Registry) Run(receipts []Receipt, names []string, p i18n.Printer) []AuditResult {
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		enabled[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var results []AuditResult
+	for _, a := range r.audits {
+		if !enabled[a.Name()] {
+			continue
+		}
+		findings := a.Run(receipts, p)
+		for i := range findings {
+			findings[i].Audit = a.Name()
+		}
+		results = append(results, AuditResult{
+			Name:     a.Name(),
+			Title:    p.Text(a.Title()),
+			Findings: findings,
+		})
+	}
+	return results
+}
+
+// ApplyAudits replaces the report audit results with the named audits from
+// reg, written in the report's language. Receipts the report already counts
+// as matching, e.g. after ApplyTolerance, get no beer vs bottles findings.
+func (r *Report) ApplyAudits(reg *Registry, names []string) {
+	r.Audits = reg.Run(r.Parsed, names, r.printer())
+	r.dropMatchedFindings()
+}
+
+// FindingCount returns the number of findings across all audits that ran.
+func (r Report) FindingCount() int {
+	n := 0
+	for _, res := range r.Audits {
+		n += len(res.Findings)
+	}
+	return n
+}
+
+func (r Report) ranAudit(name string) bool {
+	for _, res := range r.Audits {
+		if res.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+type bottlesAudit struct{}
+
+// NewBottlesAudit checks that the beer poured on a receipt matches the PET bottles sold with it.
+func NewBottlesAudit() Audit {
+	return bottlesAudit{}
+}
+
+func (bottlesAudit) Name() string  { return AuditBottles }
+func (bottlesAudit) Title() string { return "Beer vs bottles" }
+
+func (bottlesAudit) Run(receipts []Receipt, p i18n.Printer) []Finding {
+	report := buildReport(receipts)
+
+	var findings []Finding
+	for _, rec := range report.Receipts {
+		if rec.Match && rec.Flag == "" {
+			continue
+		}
+		findings = append(findings, receiptFinding(rec, p))
+	}
+	for _, rec := range report.Refunds {
+		if rec.Flag == "" {
+			continue
+		}
+		findings = append(findings, receiptFinding(rec, p))
+	}
+	return findings
+}
+
+// receiptFinding is the mismatch card of a receipt.
+func receiptFinding(rec ReceiptReport, p i18n.Printer) Finding {
+	timePart := "-"
+	if rec.IssuedAt != "" {
+		timePart = rec.IssuedAt
+	}
+
+	f := Finding{
+		Severity:  SeverityWarning,
+		Subject:   p.Sprintf("Receipt %s", rec.ReceiptNo),
+		ReceiptNo: rec.ReceiptNo,
+		Register:  rec.Register,
+		Message:   p.Sprintf("difference %s", formatDiff(rec.DiffML)),
+		Details: []string{
+			p.Sprintf("Time: %s", timePart),
+			p.Sprintf("Total beer: %s", formatLiters(rec.BeerML)),
+			p.Sprintf("Total bottles: %s", formatLiters(rec.BottleTotalML)),
+			p.Sprintf("Bottles: %s", formatBottleList(rec.BottleByML, rec.BottleOrder)),
+		},
+	}
+	if rec.Refund {
+		f.Subject = p.Sprintf("Refund %s", rec.ReceiptNo)
+	}
+	if rec.Flag != "" {
+		f.Message = flagText(rec.Flag, p)
+		f.Details = append(f.Details, p.Sprintf("Difference: %s", formatDiff(rec.DiffML)))
+	}
+	switch {
+	case rec.Refund && rec.RefundOfRefund:
+		f.Details = append(f.Details, p.Sprintf("Refund of: %s (also a refund)", rec.RefundOf))
+	case rec.Refund && rec.RefundOf != "":
+		f.Details = append(f.Details, p.Sprintf("Refund of: %s (not in this file)", rec.RefundOf))
+	}
+	if len(rec.RefundedBy) > 0 {
+		f.Details = append(f.Details, p.Sprintf("Refunded by: %s", strings.Join(rec.RefundedBy, ", ")))
+	}
+	return f
+}
+
+// flagText translates the notes of a receipt flag, as joined by appendNote.
+func flagText(flag string, p i18n.Printer) string {
+	notes := strings.Split(flag, "; ")
+	for i, note := range notes {
+		if rest, ok := strings.CutPrefix(note, "refund "); ok {
+			if no, inner, ok := strings.Cut(rest, ": "); ok {
+				notes[i] = p.Sprintf("refund %s: %s", no, p.Text(inner))
+				continue
+			}
+		}
+		notes[i] = p.Text(note)
+	}
+	return strings.Join(notes, "; ")
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+type stubAudit struct {
+	name     string
+	findings []Finding
+}
+
+func (a stubAudit) Name() string  { return a.name }
+func (a stubAudit) Title() string { return "Stub " + a.name }
+func (a stubAudit) Run(_ []Receipt, _ i18n.Printer) []Finding {
+	return append([]Finding(nil), a.findings...)
+}
+
+func TestRegistry_RegisterDuplicate(t *testing.T) {
+	reg := NewRegistry(stubAudit{name: "a"})
+	if err := reg.Register(stubAudit{name: "a"}); err == nil {
+		t.Fatal("expected duplicate registration error")
+	}
+	if _, ok := reg.Lookup(" A "); !ok {
+		t.Fatal("expected case-insensitive lookup")
+	}
+}
+
+func TestRegistry_RunEnabledInOrder(t *testing.T) {
+	reg := NewRegistry(
+		stubAudit{name: "first", findings: []Finding{{Message: "one"}}},
+		stubAudit{name: "second", findings: []Finding{{Message: "two"}}},
+		stubAudit{name: "third"},
+	)
+	results := reg.Run(nil, []string{"third", "first", "unknown"}, i18n.For(i18n.English))
+	if len(results) != 2 || results[0].Name != "first" || results[1].Name != "third" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[0].Findings[0].Audit != "first" {
+		t.Fatalf("expected finding to carry audit name, got: %+v", results[0].Findings[0])
+	}
+}
+
+func TestReport_ApplyAuditsWithoutBottles(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reg := NewRegistry(NewBottlesAudit(), stubAudit{name: "stub", findings: []Finding{{Subject: "R1", Message: "looks odd"}}})
+	report.ApplyAudits(reg, []string{"stub"})
+	text := report.FormatText()
+	if strings.Contains(text, "mismatch") || !strings.Contains(text, "===== Stub stub (1) =====\nR1: looks odd") {
+		t.Fatalf("unexpected text: %s", text)
+	}
+	if NewPhrasebook(rand.NewSource(1)).MismatchRemark(report, ToneSnarky, nil) != "" {
+		t.Fatal("expected no snark when bottles audit is disabled")
+	}
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	data, err := json.Marshal(Finding{Severity: SeverityCritical})
+	if err != nil || !strings.Contains(string(data), `"severity":"critical"`) {
+		t.Fatalf("expected the severity by name, got %s (%v)", data, err)
+	}
+	var f Finding
+	if err := json.Unmarshal([]byte(`{"severity":"warning"}`), &f); err != nil || f.Severity != SeverityWarning {
+		t.Fatalf("expected a warning, got %v (%v)", f.Severity, err)
+	}
+	for _, raw := range []string{`{"severity":"fatal"}`, `{"severity":1}`} {
+		if err := json.Unmarshal([]byte(raw), &f); err == nil {
+			t.Fatalf("%s: expected an error", raw)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnProfile names the header of every column the processor reads, so
+// exports from other POS systems or with renamed columns can be mapped.
+type ColumnProfile struct {
+	Name      string
+	Receipt   string
+	Category  string
+	Product   string
+	IssuedAt  string
+	Quantity  string
+	Original  string
+	Register  string
+	VAT       string
+	Operators []string
+}
+
+// DefaultProfile matches the export of the shop's POS.
+var DefaultProfile = ColumnProfile{
+	Name:      "default",
+	Receipt:   headerReceipt,
+	Category:  headerCategory,
+	Product:   headerProduct,
+	IssuedAt:  headerIssuedAt,
+	Quantity:  headerQuantity,
+	Original:  headerOriginal,
+	Register:  headerRegister,
+	VAT:       headerVAT,
+	Operators: operatorHeaders,
+}
+
+// ParseColumnProfiles parses "name:field=Header,field=Header;name2:..." into
+// profiles. Fields are receipt, category, product, issued, quantity,
+// original, register, vat and operator, which may be given more than once.
+// Fields left out keep the header of DefaultProfile.
+func ParseColumnProfiles(raw string) ([]ColumnProfile, error) {
+	var profiles []ColumnProfile
+	seen := map[string]bool{DefaultProfile.Name: true}
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, fields, ok := strings.Cut(part, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || strings.ContainsAny(name, " ,=") {
+			return nil, fmt.Errorf("invalid column profile: %s", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column profile: %s", name)
+		}
+		seen[name] = true
+
+		profile := DefaultProfile
+		profile.Name = name
+		var operators []string
+		for _, field := range strings.Split(fields, ",") {
+			key, header, ok := strings.Cut(field, "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			header = strings.TrimSpace(header)
+			if !ok || header == "" {
+				return nil, fmt.Errorf("invalid column mapping in profile %s: %s", name, field)
+			}
+			switch key {
+			case "receipt":
+				profile.Receipt = header
+			case "category":
+				profile.Category = header
+			case "product":
+				profile.Product = header
+			case "issued":
+				profile.IssuedAt = header
+			case "quantity":
+				profile.Quantity = header
+			case "original":
+				profile.Original = header
+			case "register":
+				profile.Register = header
+			case "vat":
+				profile.VAT = header
+			case "operator":
+				operators = append(operators, header)
+			default:
+				return nil, fmt.Errorf("unknown column %q in profile %s", key, name)
+			}
+		}
+		if len(operators) > 0 {
+			profile.Operators = operators
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (p ColumnProfile) isOperator(header string) bool {
+	for _, name := range p.Operators {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
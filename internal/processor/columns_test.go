@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseColumnProfiles(t *testing.T) {
+	profiles, err := ParseColumnProfiles("Dotykacka: receipt=Číslo účtenky, product=Název, operator=Číšník, operator=Kasír; empty:vat=Sazba")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got: %+v", profiles)
+	}
+	p := profiles[0]
+	if p.Name != "dotykacka" || p.Receipt != "Číslo účtenky" || p.Product != "Název" || p.Category != headerCategory {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+	if len(p.Operators) != 2 || !p.isOperator("kasír") {
+		t.Fatalf("unexpected operators: %+v", p.Operators)
+	}
+	if len(profiles[1].Operators) != len(operatorHeaders) {
+		t.Fatalf("expected default operators, got: %+v", profiles[1].Operators)
+	}
+
+	for _, raw := range []string{"nameonly", "x:unknown=Y", "x:receipt=", "default:receipt=X", "a:vat=X;a:vat=Y"} {
+		if _, err := ParseColumnProfiles(raw); err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestProcessFileProfile(t *testing.T) {
+	profiles, err := ParseColumnProfiles("other:receipt=Doklad,category=Skupina,product=Položka,issued=Čas,quantity=Množství")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "other.csv")
+	data := "Doklad;Skupina;Položka;Čas;Množství\nR1;Pivovar Test;Beer;2026-02-06 10:00:00;1\nR1;PET láhve;Láhev 1 l;2026-02-06 10:00:00;1\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	report, err := ProcessFileProfile(path, profiles[0])
+	if err != nil || report.TotalReceipts != 1 || report.MismatchCount != 0 {
+		t.Fatalf("unexpected report: %+v (%v)", report, err)
+	}
+	if _, err := ProcessFile(path); err == nil || !strings.Contains(err.Error(), headerReceipt) {
+		t.Fatalf("expected the default profile to miss columns, got: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+
+	"bigbrother/internal/i18n"
+)
+
+// WriteJSON writes the full report, including parsed rows and audit findings.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteXLSX writes the report as a workbook with summary, sales, mismatch and
+// finding sheets.
+func (r Report) WriteXLSX(w io.Writer) error {
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	summary := f.GetSheetName(f.GetActiveSheetIndex())
+	if err := f.SetSheetName(summary, "Summary"); err != nil {
+		return fmt.Errorf("rename sheet: %w", err)
+	}
+
+	sheets := []struct {
+		name string
+		rows [][]any
+	}{
+		{"Summary", [][]any{
+			{"Receipts checked", r.TotalReceipts},
+			{"Mismatches", r.MismatchCount},
+			{"Standalone refunds", len(r.Refunds)},
+			{"Suspicious refunds", r.SuspiciousRefunds},
+			{"Beer sold (L)", liters(r.BeerTotalML)},
+			{"Findings", r.FindingCount()},
+		}},
+		{"Products", r.productRows()},
+		{"Categories", r.categoryRows()},
+		{"Shifts", r.shiftRows()},
+		{"Mismatches", r.mismatchRows()},
+		{"Findings", r.findingRows()},
+	}
+
+	for i, sheet := range sheets {
+		if i > 0 {
+			if _, err := f.NewSheet(sheet.name); err != nil {
+				return fmt.Errorf("create sheet %s: %w", sheet.name, err)
+			}
+		}
+		for rowIdx, row := range sheet.rows {
+			cell, err := excelize.CoordinatesToCellName(1, rowIdx+1)
+			if err != nil {
+				return err
+			}
+			if err := f.SetSheetRow(sheet.name, cell, &row); err != nil {
+				return fmt.Errorf("write sheet %s: %w", sheet.name, err)
+			}
+		}
+	}
+
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("write workbook: %w", err)
+	}
+	return nil
+}
+
+func (r Report) productRows() [][]any {
+	rows := [][]any{{"Category", "Product", "Liters", "Share"}}
+	for _, p := range r.Products {
+		rows = append(rows, []any{p.Category, p.Product, liters(p.ML), p.Share})
+	}
+	return rows
+}
+
+func (r Report) categoryRows() [][]any {
+	rows := [][]any{{"Category", "Liters", "Share"}}
+	for _, c := range r.Categories {
+		rows = append(rows, []any{c.Category, liters(c.ML), c.Share})
+	}
+	return rows
+}
+
+func (r Report) shiftRows() [][]any {
+	rows := [][]any{{"Shift", "Hours", "Receipts", "Beer (L)", "Mismatches"}}
+	for _, s := range r.Shifts {
+		rows = append(rows, []any{s.Name, s.Hours, s.Receipts, liters(s.BeerML), s.Mismatches})
+	}
+	return rows
+}
+
+func (r Report) mismatchRows() [][]any {
+	// Like the headers, the resolution stays in English in the export.
+	en := i18n.For(i18n.English)
+	rows := [][]any{{"Receipt", "Issued", "Beer (L)", "Bottles (L)", "Difference (L)", "Bottles", "Refund", "Note", "Resolution"}}
+	add := func(rec ReceiptReport) {
+		rows = append(rows, []any{
+			rec.ReceiptNo,
+			rec.IssuedAt,
+			liters(rec.BeerML),
+			liters(rec.BottleTotalML),
+			liters(rec.DiffML),
+			formatBottleList(rec.BottleByML, rec.BottleOrder),
+			rec.Refund,
+			rec.Flag,
+			ResolutionTitle(rec.Resolution, en),
+		})
+	}
+	for _, rec := range r.Receipts {
+		if !rec.Match || rec.Flag != "" {
+			add(rec)
+		}
+	}
+	for _, rec := range r.Refunds {
+		add(rec)
+	}
+	return rows
+}
+
+func (r Report) findingRows() [][]any {
+	rows := [][]any{{"Audit", "Severity", "Subject", "Receipt", "Message"}}
+	for _, res := range r.Audits {
+		for _, f := range res.Findings {
+			rows = append(rows, []any{res.Title, f.Severity.String(), f.Subject, f.ReceiptNo, f.Message})
+		}
+	}
+	return rows
+}
+
+func liters(ml int64) float64 {
+	return float64(ml) / 1000.0
+}
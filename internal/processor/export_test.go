@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReport_ProductStatsAndExports(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Raven", "IPA", "2026-02-06 10:00:00", "1,5"},
+		{"R1", "PET láhve", "Láhev 1,5 l", "2026-02-06 10:00:00", "1"},
+		{"R2", "Pivovar Raven", "IPA", "2026-02-06 11:00:00", "1"},
+		{"R2", "Pivovar Prazdroj", "Pilsner", "2026-02-06 11:00:00", "1,5"},
+		{"R2", "PET láhve", "Láhev 0,5 l", "2026-02-06 11:00:00", "1"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.BeerTotalML != 4000 || len(report.Products) != 2 || len(report.Categories) != 2 {
+		t.Fatalf("unexpected stats: %+v %+v", report.Products, report.Categories)
+	}
+	top := report.Products[0]
+	if top.Product != "IPA" || top.ML != 2500 || top.Share != 0.625 {
+		t.Fatalf("unexpected top product: %+v", top)
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := report.WriteJSON(&jsonBuf); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(jsonBuf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if decoded.MismatchCount != 1 || len(decoded.Products) != 2 || len(decoded.Parsed) != 2 {
+		t.Fatalf("unexpected decoded report: %+v", decoded)
+	}
+
+	var xlsxBuf bytes.Buffer
+	if err := report.WriteXLSX(&xlsxBuf); err != nil {
+		t.Fatalf("write xlsx: %v", err)
+	}
+	f, err := excelize.OpenReader(&xlsxBuf)
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	product, err := f.GetCellValue("Products", "B2")
+	if err != nil || product != "IPA" {
+		t.Fatalf("expected IPA in Products!B2, got %q (%v)", product, err)
+	}
+	receipt, err := f.GetCellValue("Mismatches", "A2")
+	if err != nil || receipt != "R2" {
+		t.Fatalf("expected R2 in Mismatches!A2, got %q (%v)", receipt, err)
+	}
+}
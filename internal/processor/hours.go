@@ -0,0 +1,236 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+// TimeRange is an opening window in minutes since midnight. A range whose end
+// is not after its start runs past midnight into the next day.
+type TimeRange struct {
+	Start int
+	End   int
+}
+
+func (r TimeRange) overnight() bool {
+	return r.End <= r.Start
+}
+
+func (r TimeRange) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", r.Start/60, r.Start%60, r.End/60, r.End%60)
+}
+
+// Schedule holds opening hours per weekday and per-date exceptions.
+// A day without ranges is closed.
+type Schedule struct {
+	Weekly   [7][]TimeRange
+	Holidays map[string][]TimeRange
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseSchedule parses weekly hours such as
+// "mon-fri=10:00-22:00;sat=12:00-23:00,23:30-02:00;sun=closed" and holiday
+// exceptions such as "2026-12-24=10:00-14:00;2026-12-25". A holiday without
+// hours is closed for the whole day.
+func ParseSchedule(weekly, holidays string) (Schedule, error) {
+	s := Schedule{Holidays: make(map[string][]TimeRange)}
+
+	for _, part := range strings.Split(weekly, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, hours, ok := strings.Cut(part, "=")
+		if !ok {
+			return Schedule{}, fmt.Errorf("invalid opening hours: %s", part)
+		}
+		weekdays, err := parseWeekdays(days)
+		if err != nil {
+			return Schedule{}, err
+		}
+		ranges, err := parseRanges(hours)
+		if err != nil {
+			return Schedule{}, err
+		}
+		for _, wd := range weekdays {
+			s.Weekly[wd] = ranges
+		}
+	}
+
+	for _, part := range strings.Split(holidays, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		date, hours, _ := strings.Cut(part, "=")
+		date = strings.TrimSpace(date)
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return Schedule{}, fmt.Errorf("invalid holiday date: %s", date)
+		}
+		ranges, err := parseRanges(hours)
+		if err != nil {
+			return Schedule{}, err
+		}
+		s.Holidays[date] = ranges
+	}
+
+	return s, nil
+}
+
+func parseWeekdays(raw string) ([]time.Weekday, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	from, to, isRange := strings.Cut(raw, "-")
+	start, ok := weekdayNames[strings.TrimSpace(from)]
+	if !ok {
+		return nil, fmt.Errorf("invalid weekday: %s", from)
+	}
+	if !isRange {
+		return []time.Weekday{start}, nil
+	}
+	end, ok := weekdayNames[strings.TrimSpace(to)]
+	if !ok {
+		return nil, fmt.Errorf("invalid weekday: %s", to)
+	}
+
+	days := []time.Weekday{start}
+	for wd := start; wd != end; {
+		wd = (wd + 1) % 7
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+func parseRanges(raw string) ([]TimeRange, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "closed") {
+		return nil, nil
+	}
+
+	var ranges []TimeRange
+	for _, part := range strings.Split(raw, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(part), "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time range: %s", part)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, TimeRange{Start: start, End: end})
+	}
+	return ranges, nil
+}
+
+func parseClock(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %s", raw)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// rangesFor returns the opening ranges of the given calendar day.
+func (s Schedule) rangesFor(day time.Time) []TimeRange {
+	if ranges, ok := s.Holidays[day.Format("2006-01-02")]; ok {
+		return ranges
+	}
+	return s.Weekly[day.Weekday()]
+}
+
+// IsOpen reports whether t falls inside the schedule. Overnight ranges of the
+// previous day are taken into account.
+func (s Schedule) IsOpen(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	for _, r := range s.rangesFor(t) {
+		if minute >= r.Start && (r.overnight() || minute < r.End) {
+			return true
+		}
+	}
+	for _, r := range s.rangesFor(t.AddDate(0, 0, -1)) {
+		if r.overnight() && minute < r.End {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Schedule) describeDay(t time.Time) string {
+	ranges := s.rangesFor(t)
+	if len(ranges) == 0 {
+		return "closed"
+	}
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+type afterHoursAudit struct {
+	schedule Schedule
+}
+
+// NewAfterHoursAudit flags receipts issued outside the opening hours.
+// Receipts with alcohol on them are reported as critical.
+func NewAfterHoursAudit(schedule Schedule) Audit {
+	return afterHoursAudit{schedule: schedule}
+}
+
+func (afterHoursAudit) Name() string  { return AuditAfterHours }
+func (afterHoursAudit) Title() string { return "After-hours sales" }
+
+func (a afterHoursAudit) Run(receipts []Receipt, p i18n.Printer) []Finding {
+	var findings []Finding
+	for _, rec := range receipts {
+		if rec.Issued.IsZero() || a.schedule.IsOpen(rec.Issued) {
+			continue
+		}
+
+		f := Finding{
+			Severity:  SeverityWarning,
+			Subject:   p.Sprintf("Receipt %s", rec.No),
+			ReceiptNo: rec.No,
+			Register:  rec.Register,
+			Message: p.Sprintf("issued %s, opening hours %s",
+				rec.Issued.Format("Mon 02.01. 15:04"), a.schedule.describeDay(rec.Issued)),
+		}
+		for _, line := range rec.Lines {
+			if isAlcoholCategory(line.Category) {
+				f.Severity = SeverityCritical
+				f.Details = append(f.Details, p.Sprintf("Alcohol: %s / %s x%s", line.Category, line.Product, line.Quantity))
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func isAlcoholCategory(category string) bool {
+	if strings.Contains(strings.ToLower(category), "nealko") {
+		return false
+	}
+	return hasPrefixFold(category, "Pivo") ||
+		hasPrefixFold(category, "Alkohol") ||
+		hasPrefixFold(category, "Víno") ||
+		hasPrefixFold(category, "Cider")
+}
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestSchedule_IsOpen(t *testing.T) {
+	s, err := ParseSchedule("mon-fri=10:00-22:00;sat=12:00-02:00;sun=closed", "2026-02-09=closed;2026-02-10=10:00-14:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		at   string
+		open bool
+	}{
+		{"2026-02-06 21:59", true},  // Friday
+		{"2026-02-06 22:00", false}, // Friday closing time
+		{"2026-02-07 23:30", true},  // Saturday overnight
+		{"2026-02-08 01:30", true},  // Sunday, still Saturday's range
+		{"2026-02-08 02:30", false}, // Sunday closed
+		{"2026-02-09 12:00", false}, // Monday holiday
+		{"2026-02-10 15:00", false}, // Tuesday short day
+		{"2026-02-11 15:00", true},  // Wednesday
+	}
+	for _, tc := range cases {
+		at, _ := time.Parse("2006-01-02 15:04", tc.at)
+		if got := s.IsOpen(at); got != tc.open {
+			t.Fatalf("%s: expected open=%v, got %v", tc.at, tc.open, got)
+		}
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	if _, err := ParseSchedule("funday=10:00-12:00", ""); err == nil {
+		t.Fatal("expected weekday error")
+	}
+	if _, err := ParseSchedule("mon=10-12", ""); err == nil {
+		t.Fatal("expected time error")
+	}
+	if _, err := ParseSchedule("", "24.12.2026"); err == nil {
+		t.Fatal("expected holiday date error")
+	}
+}
+
+func TestAfterHoursAudit_AlcoholIsCritical(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "06.02.2026 23:10:00", "1"},
+		{"R2", "Nealko nápoje", "Kofola", "06.02.2026 23:20:00", "1"},
+		{"R3", "Pivovar Test", "Beer", "06.02.2026 21:00:00", "1"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s, err := ParseSchedule("mon-sun=10:00-22:00", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	findings := NewAfterHoursAudit(s).Run(report.Parsed, i18n.For(i18n.English))
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got: %+v", findings)
+	}
+	if findings[0].ReceiptNo != "R1" || findings[0].Severity != SeverityCritical || len(findings[0].Details) != 1 {
+		t.Fatalf("unexpected alcohol finding: %+v", findings[0])
+	}
+	if findings[1].ReceiptNo != "R2" || findings[1].Severity != SeverityWarning {
+		t.Fatalf("unexpected non-alcohol finding: %+v", findings[1])
+	}
+}
@@ -0,0 +1,143 @@
+package processor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatHTML renders the report for Telegram's HTML parse mode: bold
+// headers, number columns aligned in monospace blocks and the details of
+// each finding in an expandable quote. Everything taken from the file is
+// escaped.
+func (r Report) FormatHTML() string {
+	return r.render(reportStyle{
+		text: html.EscapeString,
+		header: func(title string, findings int) string {
+			return fmt.Sprintf("\n<b>%s (%d)</b>\n", html.EscapeString(title), findings)
+		},
+		finding:  formatFindingHTML,
+		sections: []string{r.shiftsHTML(), r.operatorsHTML(), r.whatSoldHTML()},
+	})
+}
+
+func formatFindingHTML(f Finding) string {
+	var b strings.Builder
+	if f.Severity == SeverityCritical {
+		b.WriteString("❗ ")
+	}
+	if f.Subject != "" {
+		b.WriteString("<b>" + html.EscapeString(f.Subject) + "</b>: ")
+	}
+	b.WriteString(html.EscapeString(f.Message))
+	b.WriteString("\n")
+	if len(f.Details) > 0 {
+		b.WriteString("<blockquote expandable>")
+		b.WriteString(html.EscapeString(strings.Join(f.Details, "\n")))
+		b.WriteString("</blockquote>\n")
+	}
+	return b.String()
+}
+
+func (r Report) shiftsHTML() string {
+	if len(r.Shifts) == 0 {
+		return ""
+	}
+
+	p := r.printer()
+	rows := [][]string{{p.Sprintf("Shift"), p.Sprintf("Receipts"), p.Sprintf("Beer"), p.Sprintf("Mismatches")}}
+	for _, s := range r.Shifts {
+		name := s.Name
+		if s.Hours != "" {
+			name += " (" + s.Hours + ")"
+		}
+		rows = append(rows, []string{name, strconv.Itoa(s.Receipts), formatLiters(s.BeerML), strconv.Itoa(s.Mismatches)})
+	}
+	return "\n<b>" + html.EscapeString(p.Sprintf("Shifts")) + "</b>\n" + formatTable(rows, 1)
+}
+
+func (r Report) operatorsHTML() string {
+	if len(r.Operators) == 0 {
+		return ""
+	}
+
+	p := r.printer()
+	rows := [][]string{{p.Sprintf("Operator"), p.Sprintf("Receipts"), p.Sprintf("Mismatches"), p.Sprintf("Rate")}}
+	for _, s := range r.Operators {
+		rows = append(rows, []string{s.Name, strconv.Itoa(s.Receipts), strconv.Itoa(s.Mismatches), fmt.Sprintf("%.0f%%", s.MismatchRate()*100)})
+	}
+	return "\n<b>" + html.EscapeString(p.Sprintf("Operators")) + "</b>\n" + formatTable(rows, 1)
+}
+
+func (r Report) whatSoldHTML() string {
+	if len(r.Products) == 0 {
+		return ""
+	}
+
+	pr := r.printer()
+	var b strings.Builder
+	b.WriteString("\n<b>" + html.EscapeString(pr.Sprintf("What sold (%s)", formatLiters(r.BeerTotalML))) + "</b>\n")
+	rows := [][]string{{"#", pr.Sprintf("Product"), pr.Sprintf("Beer"), pr.Sprintf("Share")}}
+	for i, p := range r.Products {
+		if i == topProducts {
+			break
+		}
+		rows = append(rows, []string{strconv.Itoa(i + 1), p.Category + " / " + p.Product, formatLiters(p.ML), fmt.Sprintf("%.0f%%", p.Share*100)})
+	}
+	b.WriteString(formatTable(rows, 2))
+	if more := len(r.Products) - topProducts; more > 0 {
+		b.WriteString(html.EscapeString(pr.Plural(more, "...and %d more product\n", "...and %d more products\n", more)))
+	}
+	if len(r.Categories) > 1 {
+		parts := make([]string, 0, topProducts+1)
+		for i, c := range r.Categories {
+			if i == topProducts {
+				parts = append(parts, pr.Sprintf("%d more", len(r.Categories)-i))
+				break
+			}
+			parts = append(parts, fmt.Sprintf("%s %s (%.0f%%)", c.Category, formatLiters(c.ML), c.Share*100))
+		}
+		b.WriteString(html.EscapeString(pr.Sprintf("By category: %s\n", strings.Join(parts, ", "))))
+	}
+	return b.String()
+}
+
+// formatTable lays rows out in a <pre> block. The first left columns hold
+// names and are aligned left; the others are numbers and aligned right.
+// Widths are counted in runes, before escaping.
+func formatTable(rows [][]string, left int) string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<pre>")
+	for n, row := range rows {
+		if n > 0 {
+			b.WriteString("\n")
+		}
+		var line strings.Builder
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case i == 0:
+				line.WriteString(cell + pad)
+			case i < left:
+				line.WriteString("  " + cell + pad)
+			default:
+				line.WriteString("  " + pad + cell)
+			}
+		}
+		b.WriteString(html.EscapeString(strings.TrimRight(line.String(), " ")))
+	}
+	b.WriteString("</pre>\n")
+	return b.String()
+}
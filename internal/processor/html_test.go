@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var (
+	htmlTag    = regexp.MustCompile(`<(/?)([a-z]+)[^>]*>`)
+	htmlEntity = regexp.MustCompile(`&(lt|gt|amp|quot|#[0-9]+);`)
+)
+
+// checkTelegramHTML fails unless s uses only tags Telegram knows, closes
+// them in order and escapes everything else.
+func checkTelegramHTML(t *testing.T, s string) {
+	t.Helper()
+
+	var open []string
+	for _, m := range htmlTag.FindAllStringSubmatch(s, -1) {
+		closing, name := m[1] == "/", m[2]
+		switch name {
+		case "b", "pre", "blockquote":
+		default:
+			t.Fatalf("unexpected tag %s in: %s", m[0], s)
+		}
+		if !closing {
+			open = append(open, name)
+			continue
+		}
+		if len(open) == 0 || open[len(open)-1] != name {
+			t.Fatalf("unbalanced %s in: %s", m[0], s)
+		}
+		open = open[:len(open)-1]
+	}
+	if len(open) > 0 {
+		t.Fatalf("unclosed tags %v in: %s", open, s)
+	}
+
+	plain := htmlEntity.ReplaceAllString(htmlTag.ReplaceAllString(s, ""), "")
+	if strings.ContainsAny(plain, "<>&") {
+		t.Fatalf("unescaped text in: %s", s)
+	}
+}
+
+func TestReport_FormatHTML(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		"Pokladník",
+	}
+	rows := [][]string{
+		{"R1", "Pivovar <Test>", `Ležák & "Co"`, "2026-02-06 10:00:00", "1", "Jana"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "1", "Jana"},
+		{"R2", "Pivovar <Test>", `Ležák & "Co"`, "2026-02-06 11:00:00", "1", "<Petr>"},
+	}
+	report, err := ProcessXLSX(writeXLSX(t, headers, rows))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := report.FormatHTML()
+	checkTelegramHTML(t, out)
+	for _, want := range []string{
+		"<b>Beer vs bottles (1)</b>",
+		"<b>Receipt R2</b>: difference -1.00L\n<blockquote expandable>Time: 2026-02-06 11:00:00\n",
+		"Pivovar &lt;Test&gt; / Ležák &amp; &#34;Co&#34;",
+		"&lt;Petr&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in: %s", want, out)
+		}
+	}
+
+	start := strings.Index(out, "<b>Operators</b>\n<pre>")
+	if start < 0 {
+		t.Fatalf("expected an operators table in: %s", out)
+	}
+	table := out[start+len("<b>Operators</b>\n<pre>"):]
+	table = htmlEntity.ReplaceAllString(table[:strings.Index(table, "</pre>")], "x")
+	lines := strings.Split(table, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two operators, got: %q", lines)
+	}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) != utf8.RuneCountInString(lines[0]) {
+			t.Fatalf("expected right-aligned columns, got: %q", lines)
+		}
+	}
+}
@@ -1,9 +1,6 @@
 package processor
 
-import (
-	"math/rand"
-	"time"
-)
+import "bigbrother/internal/i18n"
 
 var matchMessages = []string{
 	"✅ Everything matches. For once.",
@@ -157,28 +154,36 @@ var snarkMismatchMessages = []string{
 	"🤦 Mismatch confirmed. Pretend to be surprised.",
 }
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
+// snarkyPools are the phrases of the snarky tone by language.
+var snarkyPools = map[string]messagePool{
+	i18n.English: {
+		PhraseMatch:         matchMessages,
+		PhraseMismatch:      mismatchMessages,
+		PhraseSnarkMatch:    snarkMatchMessages,
+		PhraseSnarkMismatch: snarkMismatchMessages,
+	},
+	i18n.Czech:     czechMessagePool,
+	i18n.Ukrainian: ukrainianMessagePool,
 }
 
-func randomMessage(messages []string) string {
-	if len(messages) == 0 {
-		return ""
-	}
-	return messages[rand.Intn(len(messages))]
-}
-
-func randomMatchMessage() string {
-	return randomMessage(matchMessages)
-}
-
-func randomMismatchMessage() string {
-	return randomMessage(mismatchMessages)
-}
-
-func randomSnark(match bool) string {
-	if match {
-		return randomMessage(snarkMatchMessages)
-	}
-	return randomMessage(snarkMismatchMessages)
+// neutralPools are the phrases of the neutral tone by language.
+var neutralPools = map[string]messagePool{
+	i18n.English: {
+		PhraseMatch:         {"All receipts are in order.", "No discrepancies found."},
+		PhraseMismatch:      {"Some receipts need attention.", "Discrepancies found."},
+		PhraseSnarkMatch:    {"No action needed.", "Nothing to follow up on."},
+		PhraseSnarkMismatch: {"Please review the receipts listed above.", "Checked mismatches can be marked with /resolve."},
+	},
+	i18n.Czech: {
+		PhraseMatch:         {"Všechny účtenky jsou v pořádku.", "Nenalezeny žádné nesrovnalosti."},
+		PhraseMismatch:      {"Některé účtenky vyžadují pozornost.", "Nalezeny nesrovnalosti."},
+		PhraseSnarkMatch:    {"Není potřeba nic dělat.", "Není co řešit."},
+		PhraseSnarkMismatch: {"Zkontrolujte prosím účtenky uvedené výše.", "Prověřené rozdíly lze označit příkazem /resolve."},
+	},
+	i18n.Ukrainian: {
+		PhraseMatch:         {"Усі чеки в порядку.", "Розбіжностей не знайдено."},
+		PhraseMismatch:      {"Деякі чеки потребують уваги.", "Знайдено розбіжності."},
+		PhraseSnarkMatch:    {"Жодних дій не потрібно.", "Нема чого з'ясовувати."},
+		PhraseSnarkMismatch: {"Перевірте, будь ласка, чеки, наведені вище.", "Перевірені розбіжності можна позначити командою /resolve."},
+	},
 }
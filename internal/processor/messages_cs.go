@@ -0,0 +1,74 @@
+package processor
+
+var czechMessagePool = messagePool{
+	PhraseMatch: {
+		"✅ Všechno sedí. Pro jednou.",
+		"😌 Všechny účtenky vyrovnané. Zázraky se dějí.",
+		"👍 Pivo a lahve jsou v souladu. Paráda.",
+		"🎯 Přesná shoda. I lahve souhlasí.",
+		"😅 Žádné rozdíly. Na to si nezvyknu.",
+		"🧾 Všechny účtenky sedí. Nuda, ale správně.",
+		"🍺 Počty souhlasí. Lahve se chovaly slušně.",
+		"✨ Čistý průběh. Není na co si stěžovat.",
+		"🙃 Všechno sedí. Skoro mě to zklamalo.",
+		"✅ Vyrovnáno. Matematika odvedla svou práci.",
+		"🟢 Žádné problémy. Vesmír je v rovnováze.",
+		"👌 Všechno v pořádku. Dnes bez dramat.",
+		"🥱 Všechno sedí. Vzbuďte mě, až nebude.",
+		"🏁 Hotovo. Všechny účtenky jsou čisté.",
+		"✅ Nula rozdílů. Nic neměňte.",
+		"🍻 Součty sedí. Na zdraví.",
+		"✅ Zkontrolováno. Dvakrát.",
+		"🎯 Trefa. Každá účtenka sedí.",
+		"😎 Všechno sedí. Povoluji.",
+		"✅ Pivo a lahve se konečně shodnou.",
+	},
+	PhraseMismatch: {
+		"⚠️ Nalezen rozdíl. Samozřejmě.",
+		"😑 Lahve a pivo se neshodnou. Zase.",
+		"🙄 Součty nesedí. Šok.",
+		"⚠️ Něco nehraje. Matematika není nadšená.",
+		"😬 Nalezeny rozdíly. Zkuste nebrečet.",
+		"🤦 Lahve a pivo spolu nevycházejí.",
+		"⚠️ Pozor, nesrovnalost. Spočítal jsem to.",
+		"🧾 Ne všechny účtenky sedí. Překvapení.",
+		"⚠️ Nalezeny rozdíly. Doufám, že máte rádi hádanky.",
+		"😒 Čísla se hádají.",
+		"🤷 Nalezeny rozdíly. Co jste čekali?",
+		"⚠️ Pivní matematika selhala. Zase.",
+		"😑 Lahve lhaly.",
+		"⚠️ Rovnováha je narušená.",
+		"😒 Lahve vs. pivo: žádná romantika.",
+		"🙄 Součty nesedí. Klasika.",
+		"⚠️ Rozdíl. Zase. Ano, zase.",
+		"🙃 Matematika je špatně. Není to moje chyba.",
+		"⚠️ Nalezeny rozdíly. Podrobnosti níže.",
+		"⚠️ Matematika nematematikuje.",
+	},
+	PhraseSnarkMatch: {
+		"🎉 Všechno sedí. Jsem skoro hrdý. Skoro.",
+		"😌 Všechno v pořádku. Hledal jsem problém. Žádný nebyl.",
+		"✅ Čisté účtenky. Asi dnes děláte svou práci.",
+		"🧠 Součty sedí. Bohové matematiky přijali vaši oběť.",
+		"😏 Přesná shoda. Zkuste to nezkazit v dalším souboru.",
+		"✅ Žádné rozdíly. Kontroloval jsem dvakrát, jen z otravnosti.",
+		"🟢 Všechno zelené. Nudím se.",
+		"😌 Sedí to. Můžete pět minut přestat panikařit.",
+	},
+	PhraseSnarkMismatch: {
+		"🙃 A jedeme znovu. Čísla si dělají, co chtějí.",
+		"😑 Překvapení, další rozdíl. Je to jako koníček.",
+		"⚠️ Měli jste jediný úkol: aby součty seděly. A přesto.",
+		"🤦 Lahve a pivo jsou zase v toxickém vztahu.",
+		"😏 Našel jsem chyby. Není zač.",
+		"⚠️ Nalezeny rozdíly. Tvařte se prosím šokovaně.",
+		"⚠️ Tahle zpráva obsahuje zklamání zdarma.",
+		"🤷 Já jsem to spočítal. Součty ne.",
+		"⚠️ Další rozdíl. V tuhle chvíli už je to tradice.",
+		"😑 Matematika je v pořádku. Data ne.",
+		"😏 Pivo a lahve mají problém s důvěrou.",
+		"😒 Proto nemůžeme mít hezké věci.",
+		"🤷 Já počítal. Vy jste dělali… něco jiného.",
+		"🤦 Rozdíl potvrzen. Předstírejte překvapení.",
+	},
+}
@@ -0,0 +1,74 @@
+package processor
+
+var ukrainianMessagePool = messagePool{
+	PhraseMatch: {
+		"✅ Усе збігається. Хоч раз.",
+		"😌 Усі чеки зійшлися. Дива трапляються.",
+		"👍 Пиво й пляшки синхронні. Класно.",
+		"🎯 Ідеальний збіг. Навіть пляшки згодні.",
+		"😅 Жодних розбіжностей. Не звикну до цього.",
+		"🧾 Усі чеки збігаються. Нудно, але правильно.",
+		"🍺 Кількості збігаються. Пляшки поводилися чемно.",
+		"✨ Чистий прогін. Нема на що скаржитися.",
+		"🙃 Усе зійшлося. Я майже розчарований.",
+		"✅ Баланс. Математика зробила свою справу.",
+		"🟢 Жодних проблем. Всесвіт у рівновазі.",
+		"👌 Усе добре. Сьогодні без драми.",
+		"🥱 Усе збігається. Розбудіть, коли ні.",
+		"🏁 Готово. Усі чеки чисті.",
+		"✅ Нуль розбіжностей. Нічого не змінюйте.",
+		"🍻 Суми збігаються. Підніміть келих.",
+		"✅ Перевірено. Двічі.",
+		"🎯 Влучно. Кожен чек збігається.",
+		"😎 Усе збігається. Дозволяю.",
+		"✅ Пляшки й пиво нарешті згодні.",
+	},
+	PhraseMismatch: {
+		"⚠️ Виявлено розбіжність. Очевидно.",
+		"😑 Пляшки й пиво не згодні. Знову.",
+		"🙄 Суми не збігаються. Шок.",
+		"⚠️ Щось не так. Математика не вражена.",
+		"😬 Знайдено розбіжності. Постарайтеся не плакати.",
+		"🤦 Пляшки й пиво не можуть порозумітися.",
+		"⚠️ Увага, розбіжність. Я порахував.",
+		"🧾 Не всі чеки збігаються. Сюрприз.",
+		"⚠️ Знайдено розбіжності. Сподіваюся, ви любите головоломки.",
+		"😒 Числа сперечаються.",
+		"🤷 Знайдено розбіжності. А чого ви чекали?",
+		"⚠️ Пивна математика провалилася. Знову.",
+		"😑 Пляшки збрехали.",
+		"⚠️ Баланс порушено.",
+		"😒 Пляшки проти пива: не історія кохання.",
+		"🙄 Суми не сходяться. Класика.",
+		"⚠️ Розбіжність. Знову. Так, знову.",
+		"🙃 Математика неправильна. Не моя провина.",
+		"⚠️ Знайдено розбіжності. Деталі нижче.",
+		"⚠️ Математика не математикує.",
+	},
+	PhraseSnarkMatch: {
+		"🎉 Усе зійшлося. Я майже пишаюся. Майже.",
+		"😌 Усе добре. Я шукав проблему. Її не було.",
+		"✅ Чисті чеки. Схоже, сьогодні ви робите свою роботу.",
+		"🧠 Суми зійшлися. Боги математики прийняли вашу жертву.",
+		"😏 Ідеальний збіг. Постарайтеся не зіпсувати наступний файл.",
+		"✅ Жодних розбіжностей. Перевірив двічі, аби подратуватися.",
+		"🟢 Усе зелене. Мені нудно.",
+		"😌 Збігається. Можна п'ять хвилин не пітніти.",
+	},
+	PhraseSnarkMismatch: {
+		"🙃 Знову те саме. Числа живуть своїм життям.",
+		"😑 Сюрприз, ще одна розбіжність. Це як хобі.",
+		"⚠️ У вас було одне завдання: щоб суми збігалися. І все ж.",
+		"🤦 Пляшки й пиво знову в токсичних стосунках.",
+		"😏 Я знайшов помилки. Нема за що.",
+		"⚠️ Виявлено розбіжності. Будь ласка, вдайте здивування.",
+		"⚠️ Цей звіт іде з безкоштовним розчаруванням.",
+		"🤷 Я порахував. Суми — ні.",
+		"⚠️ Ще одна розбіжність. Це вже традиція.",
+		"😑 З математикою все гаразд. З даними — ні.",
+		"😏 У пива й пляшок проблеми з довірою.",
+		"😒 Ось чому ми не можемо мати гарних речей.",
+		"🤷 Я рахував. Ви робили… щось інше.",
+		"🤦 Розбіжність підтверджено. Вдайте, що здивовані.",
+	},
+}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OperatorStat counts checked receipts and mismatches of one cashier.
+type OperatorStat struct {
+	Name       string `json:"name"`
+	Receipts   int    `json:"receipts"`
+	Mismatches int    `json:"mismatches"`
+}
+
+func (s OperatorStat) MismatchRate() float64 {
+	if s.Receipts == 0 {
+		return 0
+	}
+	return float64(s.Mismatches) / float64(s.Receipts)
+}
+
+// OperatorStats groups receipts by operator, ordered by name. It returns nil
+// when no receipt has an operator, i.e. the export has no operator column.
+func OperatorStats(receipts []ReceiptReport) []OperatorStat {
+	byName := make(map[string]*OperatorStat)
+	for _, rec := range receipts {
+		if rec.Operator == "" {
+			continue
+		}
+		stat := byName[rec.Operator]
+		if stat == nil {
+			stat = &OperatorStat{Name: rec.Operator}
+			byName[rec.Operator] = stat
+		}
+		stat.Receipts++
+		if rec.chargedToOperator() {
+			stat.Mismatches++
+		}
+	}
+	return sortedOperators(byName)
+}
+
+func sortedOperators(byName map[string]*OperatorStat) []OperatorStat {
+	if len(byName) == 0 {
+		return nil
+	}
+	stats := make([]OperatorStat, 0, len(byName))
+	for _, stat := range byName {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// RankOperators orders operators with at least minReceipts receipts by
+// mismatch rate, worst first. The rest is returned separately, by name.
+func RankOperators(stats []OperatorStat, minReceipts int) (ranked, unranked []OperatorStat) {
+	for _, stat := range stats {
+		if stat.Receipts >= minReceipts {
+			ranked = append(ranked, stat)
+		} else {
+			unranked = append(unranked, stat)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].MismatchRate() != ranked[j].MismatchRate() {
+			return ranked[i].MismatchRate() > ranked[j].MismatchRate()
+		}
+		return ranked[i].Receipts > ranked[j].Receipts
+	})
+	return ranked, unranked
+}
+
+func (r Report) formatOperators() string {
+	if len(r.Operators) == 0 {
+		return ""
+	}
+
+	p := r.printer()
+	var b strings.Builder
+	b.WriteString(p.Sprintf("\n===== Operators =====\n"))
+	for _, s := range r.Operators {
+		b.WriteString(fmt.Sprintf("%s: %s, %s (%.0f%%)\n", s.Name, receiptCount(p, s.Receipts), mismatchCount(p, s.Mismatches), s.MismatchRate()*100))
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestReport_OperatorBreakdown(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		"Pokladník",
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1", "Jana"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "1", "Jana"},
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "1", "Petr"},
+		{"R3", "Pivovar Test", "Beer", "2026-02-06 12:00:00", "1", "Jana"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Operators) != 2 {
+		t.Fatalf("expected 2 operators, got: %+v", report.Operators)
+	}
+	jana, petr := report.Operators[0], report.Operators[1]
+	if jana.Name != "Jana" || jana.Receipts != 2 || jana.Mismatches != 1 {
+		t.Fatalf("unexpected Jana: %+v", jana)
+	}
+	if petr.Name != "Petr" || petr.Receipts != 1 || petr.Mismatches != 1 {
+		t.Fatalf("unexpected Petr: %+v", petr)
+	}
+	if !strings.Contains(report.FormatText(), "Jana: 2 receipts, 1 mismatch (50%)") {
+		t.Fatalf("expected operator line, got: %s", report.FormatText())
+	}
+
+	report.Lang = i18n.Czech
+	text := report.FormatText()
+	if !strings.Contains(text, "Jana: 2 účtenky, 1 rozdíl (50%)") || !strings.Contains(text, "Nalezeny 2 rozdíly.") {
+		t.Fatalf("expected a Czech report, got: %s", text)
+	}
+}
+
+func TestRankOperators(t *testing.T) {
+	stats := []OperatorStat{
+		{Name: "Jana", Receipts: 20, Mismatches: 2},
+		{Name: "Olga", Receipts: 25, Mismatches: 5},
+		{Name: "Petr", Receipts: 3, Mismatches: 3},
+	}
+	ranked, unranked := RankOperators(stats, 20)
+	if len(ranked) != 2 || ranked[0].Name != "Olga" || ranked[1].Name != "Jana" || ranked[1].Receipts != 20 {
+		t.Fatalf("unexpected ranking: %+v", ranked)
+	}
+	if len(unranked) != 1 || unranked[0].Name != "Petr" {
+		t.Fatalf("unexpected unranked: %+v", unranked)
+	}
+}
@@ -0,0 +1,275 @@
+package processor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"bigbrother/internal/i18n"
+)
+
+// Tones of the phrases added to reports. No-emoji uses the snarky phrases
+// with the emoji removed.
+const (
+	ToneSnarky  = "snarky"
+	ToneNeutral = "neutral"
+	ToneNoEmoji = "no-emoji"
+)
+
+// Kinds of phrases. A clean report opens with a match phrase. The remark
+// after a report with mismatches is a mismatch phrase followed by a
+// snark-mismatch phrase; the remark after a clean report is a snark-match
+// phrase.
+const (
+	PhraseMatch         = "match"
+	PhraseMismatch      = "mismatch"
+	PhraseSnarkMatch    = "snark-match"
+	PhraseSnarkMismatch = "snark-mismatch"
+)
+
+// PhraseKinds lists the kinds of phrases in the order they are documented.
+var PhraseKinds = []string{PhraseMatch, PhraseMismatch, PhraseSnarkMatch, PhraseSnarkMismatch}
+
+// maxToneName keeps "set:tone:<name>" within Telegram's callback data limit.
+const maxToneName = 32
+
+// messagePool holds the phrases of one language by kind.
+type messagePool map[string][]string
+
+// PhrasePack is a set of phrases for one tone and language, loaded from a
+// file. A pack for a built-in tone adds to its phrases; any other name adds
+// a tone.
+type PhrasePack struct {
+	Tone    string
+	Lang    string
+	Phrases map[string][]string
+}
+
+// Phrasebook picks the phrases of reports and remarks. It is safe for
+// concurrent use.
+type Phrasebook struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	tones map[string]map[string]messagePool
+}
+
+// NewPhrasebook returns a phrasebook with the built-in tones that draws
+// from src, so a fixed seed gives the same phrases every run.
+func NewPhrasebook(src rand.Source) *Phrasebook {
+	return &Phrasebook{
+		rng: rand.New(src),
+		tones: map[string]map[string]messagePool{
+			ToneSnarky:  snarkyPools,
+			ToneNeutral: neutralPools,
+		},
+	}
+}
+
+// Tones returns the built-in tones followed by the ones added by packs.
+func (b *Phrasebook) Tones() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var custom []string
+	for tone := range b.tones {
+		if tone != ToneSnarky && tone != ToneNeutral {
+			custom = append(custom, tone)
+		}
+	}
+	sort.Strings(custom)
+	return append([]string{ToneSnarky, ToneNeutral, ToneNoEmoji}, custom...)
+}
+
+// HasTone reports whether tone can be picked.
+func (b *Phrasebook) HasTone(tone string) bool {
+	return slices.Contains(b.Tones(), tone)
+}
+
+// AddPack adds the phrases of pack to its tone.
+func (b *Phrasebook) AddPack(pack PhrasePack) error {
+	if err := validateToneName(pack.Tone); err != nil {
+		return err
+	}
+	if pack.Tone == ToneNoEmoji {
+		return fmt.Errorf("tone %s uses the %s phrases, add them there", ToneNoEmoji, ToneSnarky)
+	}
+	if !slices.Contains(i18n.Languages, pack.Lang) {
+		return fmt.Errorf("unknown language %q", pack.Lang)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The built-in pools are shared, so the maps are copied before they
+	// change and slices are clipped before anything is appended.
+	langs := make(map[string]messagePool, len(b.tones[pack.Tone])+1)
+	for lang, pool := range b.tones[pack.Tone] {
+		langs[lang] = pool
+	}
+	pool := make(messagePool, len(PhraseKinds))
+	for kind, phrases := range langs[pack.Lang] {
+		pool[kind] = phrases
+	}
+	for kind, phrases := range pack.Phrases {
+		pool[kind] = append(slices.Clip(pool[kind]), phrases...)
+	}
+	langs[pack.Lang] = pool
+	b.tones[pack.Tone] = langs
+	return nil
+}
+
+// Pick returns a random phrase of the given tone, language and kind, or ""
+// when there is none. extra are phrases the chat added; they are picked
+// alongside the built-in ones. Unknown tones use the snarky phrases and
+// languages without phrases of the kind fall back to English.
+func (b *Phrasebook) Pick(tone, lang, kind string, extra []string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	noEmoji := tone == ToneNoEmoji
+	langs, ok := b.tones[tone]
+	if !ok {
+		langs = b.tones[ToneSnarky]
+	}
+	phrases := langs[lang][kind]
+	if len(phrases) == 0 {
+		phrases = langs[i18n.English][kind]
+	}
+	if len(extra) > 0 {
+		phrases = append(slices.Clip(phrases), extra...)
+	}
+	if len(phrases) == 0 {
+		return ""
+	}
+	phrase := phrases[b.rng.Intn(len(phrases))]
+	if noEmoji {
+		phrase = stripEmoji(phrase)
+	}
+	return phrase
+}
+
+// MismatchRemark returns the remark after a report with mismatches, or ""
+// when there are none or the bottles audit did not run.
+func (b *Phrasebook) MismatchRemark(r Report, tone string, extra map[string][]string) string {
+	if r.MismatchCount == 0 || !r.ranAudit(AuditBottles) {
+		return ""
+	}
+	lines := []string{
+		b.Pick(tone, r.Lang, PhraseMismatch, extra[PhraseMismatch]),
+		b.Pick(tone, r.Lang, PhraseSnarkMismatch, extra[PhraseSnarkMismatch]),
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// MatchRemark returns a remark for a report without mismatches, for chats
+// that want one every time.
+func (b *Phrasebook) MatchRemark(r Report, tone string, extra map[string][]string) string {
+	if r.MismatchCount != 0 || r.TotalReceipts == 0 || !r.ranAudit(AuditBottles) {
+		return ""
+	}
+	return b.Pick(tone, r.Lang, PhraseSnarkMatch, extra[PhraseSnarkMatch])
+}
+
+// ApplyPhrases picks the phrase a clean report opens with. It runs after
+// everything that changes the mismatch count.
+func (r *Report) ApplyPhrases(b *Phrasebook, tone string, extra map[string][]string) {
+	r.MatchPhrase = ""
+	if r.MismatchCount == 0 {
+		r.MatchPhrase = b.Pick(tone, r.Lang, PhraseMatch, extra[PhraseMatch])
+	}
+}
+
+// LoadPhrasePacks reads every pack in dir. Packs are text files named
+// <tone>.txt for English or <tone>.<lang>.txt, see ParsePhrasePack.
+func LoadPhrasePacks(dir string) ([]PhrasePack, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("list phrase packs: %w", err)
+	}
+	var packs []PhrasePack
+	for _, path := range paths {
+		tone, lang, ok := strings.Cut(strings.TrimSuffix(filepath.Base(path), ".txt"), ".")
+		if !ok {
+			lang = i18n.English
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open phrase pack: %w", err)
+		}
+		pack, err := ParsePhrasePack(tone, lang, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
+}
+
+// ParsePhrasePack reads phrases one per line, each under a [match],
+// [mismatch], [snark-match] or [snark-mismatch] header. Blank lines and
+// lines starting with # are ignored.
+func ParsePhrasePack(tone, lang string, r io.Reader) (PhrasePack, error) {
+	pack := PhrasePack{Tone: strings.ToLower(tone), Lang: strings.ToLower(lang), Phrases: make(map[string][]string)}
+	if err := validateToneName(pack.Tone); err != nil {
+		return PhrasePack{}, err
+	}
+
+	kind := ""
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			kind = strings.ToLower(strings.TrimSpace(text[1 : len(text)-1]))
+			if !slices.Contains(PhraseKinds, kind) {
+				return PhrasePack{}, fmt.Errorf("line %d: unknown phrase kind %q", line, kind)
+			}
+		case kind == "":
+			return PhrasePack{}, fmt.Errorf("line %d: phrase before the first [kind] header", line)
+		default:
+			pack.Phrases[kind] = append(pack.Phrases[kind], text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return PhrasePack{}, fmt.Errorf("read phrase pack: %w", err)
+	}
+	return pack, nil
+}
+
+func validateToneName(name string) error {
+	if name == "" || len(name) > maxToneName {
+		return fmt.Errorf("invalid tone name %q", name)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return fmt.Errorf("invalid tone name %q, use a-z, 0-9 and -", name)
+		}
+	}
+	return nil
+}
+
+// stripEmoji removes pictographs, their variation selectors, joiners and
+// skin tone modifiers, and the spaces they leave behind.
+func stripEmoji(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.Is(unicode.So, r),
+			r == '\u200d', r == '\ufe0f',
+			r >= 0x1f3fb && r <= 0x1f3ff:
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
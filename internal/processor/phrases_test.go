@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestPhrasebook_Seeded(t *testing.T) {
+	pick := func() []string {
+		book := NewPhrasebook(rand.NewSource(42))
+		var out []string
+		for i := 0; i < 5; i++ {
+			out = append(out, book.Pick(ToneSnarky, i18n.English, PhraseSnarkMismatch, nil))
+		}
+		return out
+	}
+	first, second := pick(), pick()
+	if !slices.Equal(first, second) {
+		t.Fatalf("expected the same phrases for the same seed, got %q and %q", first, second)
+	}
+}
+
+func TestPhrasebook_Tones(t *testing.T) {
+	book := NewPhrasebook(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		phrase := book.Pick(ToneNoEmoji, i18n.Czech, PhraseMismatch, nil)
+		if phrase == "" || phrase != stripEmoji(phrase) || strings.HasPrefix(phrase, " ") {
+			t.Fatalf("expected a phrase without emoji, got %q", phrase)
+		}
+	}
+	if phrase := book.Pick(ToneNeutral, i18n.Ukrainian, PhraseMatch, nil); !slices.Contains(neutralPools[i18n.Ukrainian][PhraseMatch], phrase) {
+		t.Fatalf("expected a neutral Ukrainian phrase, got %q", phrase)
+	}
+	if phrase := book.Pick(ToneNeutral, i18n.English, PhraseMatch, []string{"mine"}); phrase == "" {
+		t.Fatal("expected a phrase")
+	}
+	if got := stripEmoji("🍺🧴 Beer equals bottles. ⚠️ 👍🏽 Done"); got != "Beer equals bottles. Done" {
+		t.Fatalf("unexpected stripped text: %q", got)
+	}
+}
+
+func TestPhrasebook_Packs(t *testing.T) {
+	dir := t.TempDir()
+	pirate := "# arr\n[snark-mismatch]\nThe bottles walked the plank.\n\n[match]\nShip shape.\n"
+	if err := os.WriteFile(filepath.Join(dir, "pirate.txt"), []byte(pirate), 0o644); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "neutral.cs.txt"), []byte("[match]\nVše sedí.\n"), 0o644); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+	packs, err := LoadPhrasePacks(dir)
+	if err != nil {
+		t.Fatalf("load packs: %v", err)
+	}
+	book := NewPhrasebook(rand.NewSource(1))
+	for _, pack := range packs {
+		if err := book.AddPack(pack); err != nil {
+			t.Fatalf("add pack %s: %v", pack.Tone, err)
+		}
+	}
+
+	if !book.HasTone("pirate") || book.Tones()[len(book.Tones())-1] != "pirate" {
+		t.Fatalf("expected the pirate tone, got %v", book.Tones())
+	}
+	if got := book.Pick("pirate", i18n.Czech, PhraseSnarkMismatch, nil); got != "The bottles walked the plank." {
+		t.Fatalf("expected the English pirate phrase, got %q", got)
+	}
+	if got := book.Pick("pirate", i18n.English, PhraseMismatch, nil); got != "" {
+		t.Fatalf("expected no phrase of a kind the pack lacks, got %q", got)
+	}
+	if n := len(book.tones[ToneNeutral][i18n.Czech][PhraseMatch]); n != len(neutralPools[i18n.Czech][PhraseMatch])+1 {
+		t.Fatalf("expected the pack to add one phrase, got %d", n)
+	}
+	if len(neutralPools[i18n.Czech][PhraseMatch]) != 2 {
+		t.Fatal("adding a pack changed the built-in phrases")
+	}
+
+	for name, text := range map[string]string{
+		"orphan.txt":     "Phrase without a kind\n",
+		"unknown.txt":    "[praise]\nGood job\n",
+		"Bad Name.txt":   "[match]\nOk\n",
+		"klingon.tl.txt": "[match]\nQapla'\n",
+	} {
+		bad := t.TempDir()
+		if err := os.WriteFile(filepath.Join(bad, name), []byte(text), 0o644); err != nil {
+			t.Fatalf("write pack: %v", err)
+		}
+		packs, err := LoadPhrasePacks(bad)
+		if err == nil {
+			err = NewPhrasebook(rand.NewSource(1)).AddPack(packs[0])
+		}
+		if err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestReport_ApplyPhrases(t *testing.T) {
+	book := NewPhrasebook(rand.NewSource(1))
+	report := Report{TotalReceipts: 1, Audits: []AuditResult{{Name: AuditBottles}}}
+	report.Receipts = []ReceiptReport{{ReceiptNo: "R1", Match: true}}
+	extra := map[string][]string{PhraseMatch: {"Our own phrase."}}
+
+	seen := false
+	for i := 0; i < 50 && !seen; i++ {
+		report.ApplyPhrases(book, ToneNeutral, extra)
+		seen = report.MatchPhrase == "Our own phrase."
+	}
+	if !seen {
+		t.Fatal("expected the chat's phrase to be picked")
+	}
+	if !strings.HasPrefix(report.FormatText(), report.MatchPhrase+"\n") {
+		t.Fatalf("expected the report to open with the phrase, got: %s", report.FormatText())
+	}
+	if book.MismatchRemark(report, ToneNeutral, nil) != "" || book.MatchRemark(report, ToneNeutral, nil) == "" {
+		t.Fatal("expected only a match remark for a clean report")
+	}
+
+	report.MismatchCount = 1
+	report.ApplyPhrases(book, ToneNeutral, extra)
+	if report.MatchPhrase != "" || book.MismatchRemark(report, ToneNeutral, nil) == "" {
+		t.Fatalf("expected a mismatch remark and no match phrase, got %+v", report)
+	}
+}
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/xuri/excelize/v2"
+
+	"bigbrother/internal/i18n"
 )
 
 const (
@@ -20,23 +22,90 @@ const (
 	headerProduct  = "Produkt"
 	headerIssuedAt = "Datum vystavení"
 	headerQuantity = "Prodané množství"
+	headerOriginal = "Původní doklad"
+	headerRegister = "Pokladna"
+	headerVAT      = "DPH"
 )
 
+// operatorHeaders are the column names POS exports use for the cashier.
+var operatorHeaders = []string{"Pokladník", "Obsluha", "Operátor", "Prodavač", "Uživatel"}
+
 type Report struct {
-	Receipts      []ReceiptReport
-	TotalReceipts int
-	MismatchCount int
+	Receipts          []ReceiptReport `json:"receipts"`
+	Refunds           []ReceiptReport `json:"refunds,omitempty"`
+	TotalReceipts     int             `json:"total_receipts"`
+	MismatchCount     int             `json:"mismatch_count"`
+	SuspiciousRefunds int             `json:"suspicious_refunds,omitempty"`
+	BeerTotalML       int64           `json:"beer_total_ml"`
+	Products          []ProductStat   `json:"products,omitempty"`
+	Categories        []CategoryStat  `json:"categories,omitempty"`
+	Shifts            []ShiftStat     `json:"shifts,omitempty"`
+	Operators         []OperatorStat  `json:"operators,omitempty"`
+	Parsed            []Receipt       `json:"parsed,omitempty"`
+	Audits            []AuditResult   `json:"audits,omitempty"`
+	Lang              string          `json:"lang,omitempty"`
+	MatchPhrase       string          `json:"match_phrase,omitempty"`
+}
+
+// Receipt is a single receipt as read from the export, with every row kept.
+type Receipt struct {
+	No         string     `json:"no"`
+	Register   string     `json:"register,omitempty"`
+	IssuedAt   string     `json:"issued_at,omitempty"`
+	Issued     time.Time  `json:"issued"`
+	OriginalNo string     `json:"original_no,omitempty"`
+	Duplicate  *Duplicate `json:"duplicate,omitempty"`
+	Operator   string     `json:"operator,omitempty"`
+	Lines      []Line     `json:"lines"`
+}
+
+// Kinds of duplicate receipt numbers.
+const (
+	// DuplicateIssuedTwice is a receipt number with rows issued at
+	// different times.
+	DuplicateIssuedTwice = "issued_twice"
+)
+
+// Duplicate tells why a receipt number looks reused: Row is the first row
+// that disagrees with the receipt, issued at IssuedAt.
+type Duplicate struct {
+	Kind     string `json:"kind"`
+	Row      int    `json:"row"`
+	IssuedAt string `json:"issued_at,omitempty"`
+}
+
+// Line is one row of a receipt. Beer and bottle quantities are parsed
+// while reading the file so audits do not have to re-validate them.
+type Line struct {
+	Row         int    `json:"row"`
+	Category    string `json:"category"`
+	Product     string `json:"product"`
+	Quantity    string `json:"quantity"`
+	VAT         string `json:"vat,omitempty"`
+	BeerML      int64  `json:"beer_ml,omitempty"`
+	BottleML    int64  `json:"bottle_ml,omitempty"`
+	BottleCount int64  `json:"bottle_count,omitempty"`
 }
 
 type ReceiptReport struct {
-	ReceiptNo     string
-	IssuedAt      string
-	BeerML        int64
-	BottleByML    map[int64]int64
-	BottleOrder   []int64
-	BottleTotalML int64
-	DiffML        int64
-	Match         bool
+	ReceiptNo     string          `json:"receipt_no"`
+	Register      string          `json:"register,omitempty"`
+	IssuedAt      string          `json:"issued_at,omitempty"`
+	Issued        time.Time       `json:"issued"`
+	BeerML        int64           `json:"beer_ml"`
+	BottleByML    map[int64]int64 `json:"bottle_by_ml,omitempty"`
+	BottleOrder   []int64         `json:"bottle_order,omitempty"`
+	BottleTotalML int64           `json:"bottle_total_ml"`
+	DiffML        int64           `json:"diff_ml"`
+	Match         bool            `json:"match"`
+	Refund        bool            `json:"refund,omitempty"`
+	RefundOf      string          `json:"refund_of,omitempty"`
+	// RefundOfRefund is set when RefundOf is in the file but is a refund too.
+	RefundOfRefund bool     `json:"refund_of_refund,omitempty"`
+	RefundedBy     []string `json:"refunded_by,omitempty"`
+	Flag           string   `json:"flag,omitempty"`
+	Operator       string   `json:"operator,omitempty"`
+	Resolution     string   `json:"resolution,omitempty"`
 }
 
 type columnIndex struct {
@@ -45,31 +114,63 @@ type columnIndex struct {
 	product  int
 	issuedAt int
 	quantity int
+	original int
+	register int
+	vat      int
+	operator int
+}
+
+// receiptID identifies a receipt within a file. Registers number their
+// receipts independently, so the same number can appear on several.
+type receiptID struct {
+	register string
+	no       string
 }
 
 type receiptAgg struct {
 	receiptNo     string
+	register      string
 	issuedAt      string
+	issued        time.Time
 	beerML        int64
 	bottleByML    map[int64]int64
 	bottleOrder   []int64
 	bottleTotalML int64
+	refund        bool
+	originalNo    string
+	// originalRefund is set when originalNo is a refund in the same file.
+	originalRefund bool
+	operator       string
+	refundedBy     []string
 }
 
+// ProcessFile processes an xlsx or CSV file, telling them apart by content
+// rather than by extension.
 func ProcessFile(path string) (Report, error) {
-	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	case ".xlsx":
-		return ProcessXLSX(path)
-	case ".csv":
-		return ProcessCSV(path)
-	default:
-		return Report{}, fmt.Errorf("unsupported file type: %s", ext)
+	return ProcessFileProfile(path, DefaultProfile)
+}
+
+// ProcessFileProfile is ProcessFile with the column headers taken from profile.
+func ProcessFileProfile(path string, profile ColumnProfile) (Report, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return Report{}, err
 	}
+	if format == FormatXLSX {
+		return processXLSX(path, profile)
+	}
+	return processCSV(path, profile)
 }
 
 func ProcessXLSX(path string) (Report, error) {
-	f, err := excelize.OpenFile(path)
+	return processXLSX(path, DefaultProfile)
+}
+
+func processXLSX(path string, profile ColumnProfile) (Report, error) {
+	f, err := excelize.OpenFile(path, excelize.Options{
+		UnzipSizeLimit:    maxXLSXUncompressed,
+		UnzipXMLSizeLimit: min(maxXLSXUncompressed, 16<<20),
+	})
 	if err != nil {
 		return Report{}, fmt.Errorf("open file: %w", err)
 	}
@@ -93,13 +194,16 @@ func ProcessXLSX(path string) (Report, error) {
 	if err != nil {
 		return Report{}, fmt.Errorf("read header: %w", err)
 	}
-	idx, err := mapHeaders(headerRow)
+	if err := checkRowLimits(headerRow, 1); err != nil {
+		return Report{}, err
+	}
+	idx, err := mapHeaders(headerRow, profile)
 	if err != nil {
 		return Report{}, err
 	}
 
-	receipts := make(map[string]*receiptAgg)
-	order := make([]string, 0, 256)
+	receipts := make(map[receiptID]*Receipt)
+	order := make([]receiptID, 0, 256)
 	rowNum := 1
 	for rows.Next() {
 		rowNum++
@@ -107,6 +211,9 @@ func ProcessXLSX(path string) (Report, error) {
 		if err != nil {
 			return Report{}, fmt.Errorf("read row %d: %w", rowNum, err)
 		}
+		if err := checkRowLimits(row, rowNum); err != nil {
+			return Report{}, err
+		}
 		if err := accumulateRow(row, rowNum, idx, receipts, &order); err != nil {
 			return Report{}, err
 		}
@@ -115,11 +222,14 @@ func ProcessXLSX(path string) (Report, error) {
 		return Report{}, fmt.Errorf("rows error: %w", err)
 	}
 
-	report := buildReport(receipts, order)
-	return report, nil
+	return newReport(receipts, order), nil
 }
 
 func ProcessCSV(path string) (Report, error) {
+	return processCSV(path, DefaultProfile)
+}
+
+func processCSV(path string, profile ColumnProfile) (Report, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return Report{}, fmt.Errorf("open file: %w", err)
@@ -142,14 +252,17 @@ func ProcessCSV(path string) (Report, error) {
 	if err != nil {
 		return Report{}, fmt.Errorf("read header: %w", err)
 	}
+	if err := checkRowLimits(headerRow, 1); err != nil {
+		return Report{}, err
+	}
 
-	idx, err := mapHeaders(headerRow)
+	idx, err := mapHeaders(headerRow, profile)
 	if err != nil {
 		return Report{}, err
 	}
 
-	receipts := make(map[string]*receiptAgg)
-	order := make([]string, 0, 256)
+	receipts := make(map[receiptID]*Receipt)
+	order := make([]receiptID, 0, 256)
 	rowNum := 1
 	for {
 		row, err := reader.Read()
@@ -160,55 +273,69 @@ func ProcessCSV(path string) (Report, error) {
 			return Report{}, fmt.Errorf("read row %d: %w", rowNum+1, err)
 		}
 		rowNum++
+		if err := checkRowLimits(row, rowNum); err != nil {
+			return Report{}, err
+		}
 		if err := accumulateRow(row, rowNum, idx, receipts, &order); err != nil {
 			return Report{}, err
 		}
 	}
 
-	report := buildReport(receipts, order)
-	return report, nil
+	return newReport(receipts, order), nil
 }
 
-func mapHeaders(headerRow []string) (columnIndex, error) {
+func mapHeaders(headerRow []string, profile ColumnProfile) (columnIndex, error) {
 	idx := columnIndex{
 		receipt:  -1,
 		category: -1,
 		product:  -1,
 		issuedAt: -1,
 		quantity: -1,
+		original: -1,
+		register: -1,
+		vat:      -1,
+		operator: -1,
 	}
 
 	for i, raw := range headerRow {
 		header := normalizeHeader(raw)
 		switch {
-		case strings.EqualFold(header, headerReceipt):
+		case strings.EqualFold(header, profile.Receipt):
 			idx.receipt = i
-		case strings.EqualFold(header, headerCategory):
+		case strings.EqualFold(header, profile.Category):
 			idx.category = i
-		case strings.EqualFold(header, headerProduct):
+		case strings.EqualFold(header, profile.Product):
 			idx.product = i
-		case strings.EqualFold(header, headerIssuedAt):
+		case strings.EqualFold(header, profile.IssuedAt):
 			idx.issuedAt = i
-		case strings.EqualFold(header, headerQuantity):
+		case strings.EqualFold(header, profile.Quantity):
 			idx.quantity = i
+		case strings.EqualFold(header, profile.Original):
+			idx.original = i
+		case strings.EqualFold(header, profile.Register):
+			idx.register = i
+		case strings.EqualFold(header, profile.VAT):
+			idx.vat = i
+		case profile.isOperator(header):
+			idx.operator = i
 		}
 	}
 
 	var missing []string
 	if idx.receipt < 0 {
-		missing = append(missing, headerReceipt)
+		missing = append(missing, profile.Receipt)
 	}
 	if idx.category < 0 {
-		missing = append(missing, headerCategory)
+		missing = append(missing, profile.Category)
 	}
 	if idx.product < 0 {
-		missing = append(missing, headerProduct)
+		missing = append(missing, profile.Product)
 	}
 	if idx.issuedAt < 0 {
-		missing = append(missing, headerIssuedAt)
+		missing = append(missing, profile.IssuedAt)
 	}
 	if idx.quantity < 0 {
-		missing = append(missing, headerQuantity)
+		missing = append(missing, profile.Quantity)
 	}
 	if len(missing) > 0 {
 		return idx, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
@@ -230,28 +357,45 @@ func getCell(row []string, idx int) string {
 	return row[idx]
 }
 
-func accumulateRow(row []string, rowNum int, idx columnIndex, receipts map[string]*receiptAgg, order *[]string) error {
+func accumulateRow(row []string, rowNum int, idx columnIndex, receipts map[receiptID]*Receipt, order *[]receiptID) error {
 	receiptNo := strings.TrimSpace(getCell(row, idx.receipt))
 	if receiptNo == "" {
 		return nil
 	}
 
-	agg := receipts[receiptNo]
-	if agg == nil {
-		agg = &receiptAgg{
-			receiptNo:  receiptNo,
-			bottleByML: make(map[int64]int64),
-		}
-		receipts[receiptNo] = agg
-		*order = append(*order, receiptNo)
+	register := strings.TrimSpace(getCell(row, idx.register))
+	id := receiptID{register: register, no: receiptNo}
+	rec := receipts[id]
+	if rec == nil {
+		rec = &Receipt{No: receiptNo, Register: register}
+		receipts[id] = rec
+		*order = append(*order, id)
 	}
 
 	category := strings.TrimSpace(getCell(row, idx.category))
 	product := strings.TrimSpace(getCell(row, idx.product))
 	issuedAt := strings.TrimSpace(getCell(row, idx.issuedAt))
 	quantity := strings.TrimSpace(getCell(row, idx.quantity))
-	if agg.issuedAt == "" && issuedAt != "" {
-		agg.issuedAt = issuedAt
+	if rec.IssuedAt == "" && issuedAt != "" {
+		rec.IssuedAt = issuedAt
+		rec.Issued, _ = parseIssuedAt(issuedAt)
+	}
+	if operator := strings.TrimSpace(getCell(row, idx.operator)); rec.Operator == "" && operator != "" {
+		rec.Operator = operator
+	}
+	if rec.Duplicate == nil && issuedAt != "" && issuedAt != rec.IssuedAt {
+		rec.Duplicate = &Duplicate{Kind: DuplicateIssuedTwice, Row: rowNum, IssuedAt: issuedAt}
+	}
+	if original := strings.TrimSpace(getCell(row, idx.original)); original != "" && original != receiptNo {
+		rec.OriginalNo = original
+	}
+
+	line := Line{
+		Row:      rowNum,
+		Category: category,
+		Product:  product,
+		Quantity: quantity,
+		VAT:      strings.TrimSpace(getCell(row, idx.vat)),
 	}
 
 	if isPivovarCategory(category) {
@@ -259,7 +403,7 @@ func accumulateRow(row []string, rowNum int, idx columnIndex, receipts map[strin
 		if err != nil {
 			return fmt.Errorf("row %d: invalid beer quantity: %w", rowNum, err)
 		}
-		agg.beerML += beerML
+		line.BeerML = beerML
 	}
 
 	if isPETCategory(category) && isBottleProduct(product) {
@@ -271,16 +415,28 @@ func accumulateRow(row []string, rowNum int, idx columnIndex, receipts map[strin
 		if err != nil {
 			return fmt.Errorf("row %d: invalid bottle quantity: %w", rowNum, err)
 		}
-		if _, ok := agg.bottleByML[bottleML]; !ok {
-			agg.bottleOrder = append(agg.bottleOrder, bottleML)
-		}
-		agg.bottleByML[bottleML] += count
-		agg.bottleTotalML += bottleML * count
+		line.BottleML = bottleML
+		line.BottleCount = count
 	}
 
+	rec.Lines = append(rec.Lines, line)
 	return nil
 }
 
+func newReport(receipts map[receiptID]*Receipt, order []receiptID) Report {
+	parsed := make([]Receipt, 0, len(order))
+	for _, id := range order {
+		parsed = append(parsed, *receipts[id])
+	}
+
+	report := buildReport(parsed)
+	report.Parsed = parsed
+	report.collectStats()
+	report.Operators = OperatorStats(report.Receipts)
+	report.ApplyAudits(defaultRegistry, DefaultAudits)
+	return report
+}
+
 func isPivovarCategory(category string) bool {
 	return hasPrefixFold(category, "Pivovar") || hasPrefixFold(category, "Pivo na čepu")
 }
@@ -322,36 +478,47 @@ func parseBottleLitersML(product string) (int64, error) {
 	return ml, nil
 }
 
-func buildReport(receipts map[string]*receiptAgg, order []string) Report {
+func buildReport(parsed []Receipt) Report {
 	result := Report{}
-	if len(receipts) == 0 {
+	if len(parsed) == 0 {
 		return result
 	}
 
+	receipts := make(map[receiptID]*receiptAgg, len(parsed))
+	order := make([]receiptID, 0, len(parsed))
+	for i := range parsed {
+		id := receiptID{register: parsed[i].Register, no: parsed[i].No}
+		receipts[id] = aggregateReceipt(&parsed[i])
+		order = append(order, id)
+	}
+
+	flags := netRefunds(receipts, order)
+
 	list := make([]ReceiptReport, 0, len(receipts))
-	for _, receiptNo := range order {
-		agg := receipts[receiptNo]
+	for _, id := range order {
+		agg := receipts[id]
 		if agg == nil {
 			continue
 		}
 		if agg.beerML == 0 && agg.bottleTotalML == 0 && len(agg.bottleByML) == 0 {
 			continue
 		}
-		diff := agg.bottleTotalML - agg.beerML
-		match := diff == 0
-		if !match {
+		rec := newReceiptReport(agg)
+		rec.RefundedBy = agg.refundedBy
+		rec.RefundOfRefund = agg.originalRefund
+		rec.Flag = flags[id]
+		if rec.Flag != "" {
+			result.SuspiciousRefunds++
+		}
+		if agg.refund {
+			rec.Refund = true
+			result.Refunds = append(result.Refunds, rec)
+			continue
+		}
+		if !rec.Match {
 			result.MismatchCount++
 		}
-		list = append(list, ReceiptReport{
-			ReceiptNo:     agg.receiptNo,
-			IssuedAt:      agg.issuedAt,
-			BeerML:        agg.beerML,
-			BottleByML:    agg.bottleByML,
-			BottleOrder:   agg.bottleOrder,
-			BottleTotalML: agg.bottleTotalML,
-			DiffML:        diff,
-			Match:         match,
-		})
+		list = append(list, rec)
 	}
 
 	result.Receipts = list
@@ -359,59 +526,261 @@ func buildReport(receipts map[string]*receiptAgg, order []string) Report {
 	return result
 }
 
-func (r Report) FormatText() string {
-	if len(r.Receipts) == 0 {
-		return "No matching beer/PET rows found."
+func aggregateReceipt(rec *Receipt) *receiptAgg {
+	agg := &receiptAgg{
+		receiptNo:  rec.No,
+		register:   rec.Register,
+		issuedAt:   rec.IssuedAt,
+		issued:     rec.Issued,
+		originalNo: rec.OriginalNo,
+		operator:   rec.Operator,
+		bottleByML: make(map[int64]int64),
 	}
+	// A receipt is a refund when it only takes beer or bottles back, or
+	// takes back more than it sells. A sale with a returned bottle is not.
+	sold, returned := false, false
+	for _, line := range rec.Lines {
+		switch {
+		case line.BeerML < 0 || line.BottleCount < 0:
+			returned = true
+		case line.BeerML > 0 || line.BottleCount > 0:
+			sold = true
+		}
+		agg.beerML += line.BeerML
+		if line.BottleML == 0 {
+			continue
+		}
+		if _, ok := agg.bottleByML[line.BottleML]; !ok {
+			agg.bottleOrder = append(agg.bottleOrder, line.BottleML)
+		}
+		agg.bottleByML[line.BottleML] += line.BottleCount
+		agg.bottleTotalML += line.BottleML * line.BottleCount
+	}
+	agg.refund = returned && (!sold || agg.beerML+agg.bottleTotalML < 0)
+	return agg
+}
 
-	if r.MismatchCount == 0 {
-		return fmt.Sprintf("%s\nChecked %d receipts. All beer vs bottles match.",
-			randomMatchMessage(),
-			r.TotalReceipts,
-		)
+func newReceiptReport(agg *receiptAgg) ReceiptReport {
+	diff := agg.bottleTotalML - agg.beerML
+	return ReceiptReport{
+		ReceiptNo:     agg.receiptNo,
+		Register:      agg.register,
+		IssuedAt:      agg.issuedAt,
+		Issued:        agg.issued,
+		BeerML:        agg.beerML,
+		BottleByML:    agg.bottleByML,
+		BottleOrder:   agg.bottleOrder,
+		BottleTotalML: agg.bottleTotalML,
+		DiffML:        diff,
+		Match:         diff == 0,
+		RefundOf:      agg.originalNo,
+		Operator:      agg.operator,
 	}
+}
+
+// netRefunds folds refund receipts into the original receipt they reference
+// when that receipt is part of the same file. Refunds that cannot be netted
+// stay in the map as standalone refunds. The returned map holds a note for
+// every receipt whose refund looks suspicious, keyed by the receipt that ends
+// up in the report.
+func netRefunds(receipts map[receiptID]*receiptAgg, order []receiptID) map[receiptID]string {
+	flags := make(map[receiptID]string)
+	for _, id := range order {
+		agg := receipts[id]
+		if agg == nil || !agg.refund {
+			continue
+		}
 
+		note := refundFlag(agg)
+		originalID, ok := findOriginal(receipts, order, id.register, agg.originalNo)
+		original := receipts[originalID]
+		if !ok || original.refund {
+			agg.originalRefund = ok
+			if note != "" {
+				flags[id] = note
+			}
+			continue
+		}
+
+		original.beerML += agg.beerML
+		for _, ml := range agg.bottleOrder {
+			if _, ok := original.bottleByML[ml]; !ok {
+				original.bottleOrder = append(original.bottleOrder, ml)
+			}
+			original.bottleByML[ml] += agg.bottleByML[ml]
+		}
+		original.bottleTotalML += agg.bottleTotalML
+		original.refundedBy = append(original.refundedBy, id.no)
+		if note != "" {
+			flags[originalID] = appendNote(flags[originalID], fmt.Sprintf("refund %s: %s", id.no, note))
+		}
+		delete(receipts, id)
+	}
+	return flags
+}
+
+// findOriginal returns the receipt a refund references. A refund is
+// usually issued on the register of the original; when it is not, the
+// number must be unique in the file to be used.
+func findOriginal(receipts map[receiptID]*receiptAgg, order []receiptID, register, no string) (receiptID, bool) {
+	if no == "" {
+		return receiptID{}, false
+	}
+	if id := (receiptID{register: register, no: no}); receipts[id] != nil {
+		return id, true
+	}
+	var found []receiptID
+	for _, id := range order {
+		if id.no == no && receipts[id] != nil {
+			found = append(found, id)
+		}
+	}
+	if len(found) != 1 {
+		return receiptID{}, false
+	}
+	return found[0], true
+}
+
+func refundFlag(agg *receiptAgg) string {
+	switch {
+	case agg.beerML < 0 && agg.bottleTotalML == 0:
+		return "beer refunded without bottles"
+	case agg.bottleTotalML < 0 && agg.beerML == 0:
+		return "bottles refunded without beer"
+	case agg.bottleTotalML != agg.beerML:
+		return "refunded beer and bottles differ"
+	default:
+		return ""
+	}
+}
+
+func appendNote(existing, note string) string {
+	if existing == "" {
+		return note
+	}
+	return existing + "; " + note
+}
+
+// FormatText renders the report in its language.
+func (r Report) FormatText() string {
+	return r.render(reportStyle{
+		text: func(s string) string { return s },
+		header: func(title string, findings int) string {
+			return fmt.Sprintf("\n===== %s (%d) =====\n", title, findings)
+		},
+		finding:  formatFinding,
+		sections: []string{r.formatShifts(), r.formatOperators(), r.formatWhatSold()},
+	})
+}
+
+// reportStyle is how render draws the parts of a report.
+type reportStyle struct {
+	text     func(string) string
+	header   func(title string, findings int) string
+	finding  func(Finding) string
+	sections []string
+}
+
+// render lays out the summary, the audit findings and the extra sections,
+// cutting them short to stay within Telegram's message limit.
+func (r Report) render(style reportStyle) string {
+	p := r.printer()
+	truncated := style.text(p.Sprintf("...truncated"))
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("Checked %d receipts. Found %d mismatches.\n", r.TotalReceipts, r.MismatchCount))
+	b.WriteString(style.text(r.summaryText()))
+	b.WriteString("\n")
 
 	limit := 3900
-	for _, rec := range r.Receipts {
-		if rec.Match {
+	for _, res := range r.Audits {
+		if len(res.Findings) == 0 {
 			continue
 		}
-		card := formatMismatchCard(rec)
-		if b.Len()+len(card) > limit {
-			b.WriteString("...truncated")
+		section := style.header(res.Title, len(res.Findings))
+		for _, finding := range res.Findings {
+			text := style.finding(finding)
+			if b.Len()+len(section)+len(text) > limit {
+				b.WriteString(section)
+				b.WriteString(truncated)
+				return strings.TrimSpace(b.String())
+			}
+			section += text
+		}
+		b.WriteString(section)
+	}
+
+	for _, section := range style.sections {
+		if section == "" {
+			continue
+		}
+		if b.Len()+len(section) > limit {
+			b.WriteString("\n" + truncated)
 			break
 		}
-		b.WriteString(card)
+		b.WriteString(section)
 	}
 
 	return strings.TrimSpace(b.String())
 }
 
-func (r Report) MismatchSnarkText() string {
+func (r Report) summaryText() string {
+	p := r.printer()
+	checked := func(n int) string {
+		return p.Plural(n, "Checked %d receipt.", "Checked %d receipts.", n)
+	}
+	if !r.ranAudit(AuditBottles) {
+		return checked(len(r.Parsed))
+	}
+	if len(r.Receipts) == 0 && len(r.Refunds) == 0 {
+		return p.Sprintf("No matching beer/PET rows found.")
+	}
+
+	var summary string
 	if r.MismatchCount == 0 {
-		return ""
+		summary = checked(r.TotalReceipts) + " " + p.Sprintf("All beer vs bottles match.")
+		if r.MatchPhrase != "" {
+			summary = r.MatchPhrase + "\n" + summary
+		}
+	} else {
+		summary = checked(r.TotalReceipts) + " " + p.Plural(r.MismatchCount, "Found %d mismatch.", "Found %d mismatches.", r.MismatchCount)
 	}
-	return randomMismatchMessage() + "\n" + randomSnark(false)
+	if len(r.Refunds) > 0 || r.SuspiciousRefunds > 0 {
+		summary += "\n" + p.Sprintf("Refunds: %d standalone, %d suspicious.", len(r.Refunds), r.SuspiciousRefunds)
+	}
+	return summary
 }
 
-func formatMismatchCard(rec ReceiptReport) string {
-	timePart := "-"
-	if rec.IssuedAt != "" {
-		timePart = rec.IssuedAt
-	}
+func (r Report) printer() i18n.Printer {
+	return i18n.For(r.Lang)
+}
 
-	return fmt.Sprintf(
-		"===== Receipt %s =====\nTime: %s\nTotal beer: %s\nTotal bottles: %s\nDifference: %s\nBottles: %s\n\n",
-		rec.ReceiptNo,
-		timePart,
-		formatLiters(rec.BeerML),
-		formatLiters(rec.BottleTotalML),
-		formatDiff(rec.DiffML),
-		formatBottleList(rec.BottleByML, rec.BottleOrder),
-	)
+func receiptCount(p i18n.Printer, n int) string {
+	return p.Plural(n, "%d receipt", "%d receipts", n)
+}
+
+func mismatchCount(p i18n.Printer, n int) string {
+	return p.Plural(n, "%d mismatch", "%d mismatches", n)
+}
+
+func formatFinding(f Finding) string {
+	var b strings.Builder
+	if f.Severity == SeverityCritical {
+		b.WriteString("❗ ")
+	}
+	if f.Subject != "" {
+		b.WriteString(f.Subject)
+		b.WriteString(": ")
+	}
+	b.WriteString(f.Message)
+	b.WriteString("\n")
+	for _, detail := range f.Details {
+		b.WriteString("  ")
+		b.WriteString(detail)
+		b.WriteString("\n")
+	}
+	if len(f.Details) > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
 }
 
 func formatBottleList(byML map[int64]int64, order []int64) string {
@@ -448,11 +817,17 @@ func parseDecimalToMilli(raw string) (int64, error) {
 	fracDigits := 0
 	sawDigit := false
 	seenSep := false
+	negative := false
 	roundDigit := int64(-1)
 
 	for i := 0; i < len(raw); i++ {
 		b := raw[i]
 		switch {
+		case b == '-':
+			if negative || sawDigit || seenSep {
+				return 0, fmt.Errorf("invalid number: %s", raw)
+			}
+			negative = true
 		case b >= '0' && b <= '9':
 			sawDigit = true
 			if !seenSep {
@@ -488,6 +863,9 @@ func parseDecimalToMilli(raw string) (int64, error) {
 	if roundDigit >= 5 {
 		ml++
 	}
+	if negative {
+		ml = -ml
+	}
 	return ml, nil
 }
 
@@ -495,11 +873,17 @@ func parseWholeNumber(raw string) (int64, bool, error) {
 	var intPart int64
 	sawDigit := false
 	seenSep := false
+	negative := false
 	nonZeroFraction := false
 
 	for i := 0; i < len(raw); i++ {
 		b := raw[i]
 		switch {
+		case b == '-':
+			if negative || sawDigit || seenSep {
+				return 0, false, fmt.Errorf("invalid number: %s", raw)
+			}
+			negative = true
 		case b >= '0' && b <= '9':
 			sawDigit = true
 			if !seenSep {
@@ -522,6 +906,9 @@ func parseWholeNumber(raw string) (int64, bool, error) {
 	if !sawDigit {
 		return 0, false, fmt.Errorf("empty value")
 	}
+	if negative {
+		intPart = -intPart
+	}
 	if nonZeroFraction {
 		return intPart, false, nil
 	}
@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/xuri/excelize/v2"
+
+	"bigbrother/internal/i18n"
 )
 
 //go:embed testData_mismatch.csv
@@ -209,14 +211,161 @@ func TestProcessXLSX_BottleProductAccentStrict(t *testing.T) {
 	}
 }
 
+func TestProcessXLSX_RefundNettedAgainstOriginal(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		headerOriginal,
+	}
+	rows := [][]string{
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "-1", "R1"},
+		{"R2", "PET láhve", "Láhev 1 l", "2026-02-06 11:00:00", "-1", "R1"},
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "2", ""},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "2", ""},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TotalReceipts != 1 || report.MismatchCount != 0 || len(report.Refunds) != 0 {
+		t.Fatalf("expected refund netted into one matching receipt, got: %+v", report)
+	}
+	rec := report.Receipts[0]
+	if rec.ReceiptNo != "R1" || rec.BeerML != 1000 || rec.BottleTotalML != 1000 {
+		t.Fatalf("unexpected netted receipt: %+v", rec)
+	}
+	if len(rec.RefundedBy) != 1 || rec.RefundedBy[0] != "R2" {
+		t.Fatalf("expected refunded by R2, got: %v", rec.RefundedBy)
+	}
+}
+
+func TestProcessXLSX_StandaloneRefundFlagged(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "1"},
+		{"R9", "Pivovar Test", "Beer", "2026-02-06 12:00:00", "-1,5"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TotalReceipts != 1 || report.MismatchCount != 0 {
+		t.Fatalf("refunds must not count as receipts or mismatches, got: %+v", report)
+	}
+	if len(report.Refunds) != 1 || report.SuspiciousRefunds != 1 {
+		t.Fatalf("expected one suspicious refund, got: %+v", report)
+	}
+	refund := report.Refunds[0]
+	if !refund.Refund || refund.BeerML != -1500 || refund.Flag != "beer refunded without bottles" {
+		t.Fatalf("unexpected refund: %+v", refund)
+	}
+	if !strings.Contains(report.FormatText(), "Refund R9: beer refunded without bottles") {
+		t.Fatalf("expected refund card in text, got: %s", report.FormatText())
+	}
+
+	report.Lang = i18n.Czech
+	report.ApplyAudits(DefaultRegistry(), []string{"bottles"})
+	if text := report.FormatText(); !strings.Contains(text, "Vratka R9: pivo vráceno bez lahví") {
+		t.Fatalf("expected a translated refund flag, got: %s", text)
+	}
+}
+
+func TestProcessXLSX_RefundOfRefund(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		headerOriginal,
+	}
+	rows := [][]string{
+		{"R8", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "-1", ""},
+		{"R9", "Pivovar Test", "Beer", "2026-02-06 12:00:00", "-1", "R8"},
+		{"R10", "Pivovar Test", "Beer", "2026-02-06 13:00:00", "-1", "R7"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Refunds) != 3 || !report.Refunds[1].RefundOfRefund || report.Refunds[2].RefundOfRefund {
+		t.Fatalf("expected only R9 to refund a refund, got: %+v", report.Refunds)
+	}
+
+	text := report.FormatText()
+	for _, want := range []string{"Refund of: R8 (also a refund)", "Refund of: R7 (not in this file)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "R8 (not in this file)") {
+		t.Fatalf("expected R8 to be found in the file:\n%s", text)
+	}
+}
+
+func TestProcessXLSX_ReturnedBottleIsNotRefund(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "2"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "-1"},
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "1"},
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "-2"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.TotalReceipts != 1 || report.MismatchCount != 0 || report.Receipts[0].Refund {
+		t.Fatalf("expected a matching sale with a returned bottle, got: %+v", report)
+	}
+	if len(report.Refunds) != 1 || report.Refunds[0].ReceiptNo != "R2" {
+		t.Fatalf("expected the net negative receipt as a refund, got: %+v", report.Refunds)
+	}
+}
+
+func TestParseDecimalToMilli_Negative(t *testing.T) {
+	ml, err := parseDecimalToMilli(" -0,5")
+	if err != nil || ml != -500 {
+		t.Fatalf("expected -500, got: %d (%v)", ml, err)
+	}
+	if _, err := parseDecimalToMilli("1-"); err == nil {
+		t.Fatal("expected error for trailing minus")
+	}
+	count, err := parseWholeCount("-2.00")
+	if err != nil || count != -2 {
+		t.Fatalf("expected -2, got: %d (%v)", count, err)
+	}
+}
+
 func TestProcessFile_Unsupported(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.txt")
-	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+	if err := os.WriteFile(path, []byte("\x7fELF\x02\x01\x01\x00"), 0o644); err != nil {
 		t.Fatalf("write file: %v", err)
 	}
 	_, err := ProcessFile(path)
-	if err == nil || !strings.Contains(err.Error(), "unsupported file type") {
-		t.Fatalf("expected unsupported file type error, got: %v", err)
+	if _, ok := IsRejected(err); !ok {
+		t.Fatalf("expected the file to be rejected, got: %v", err)
 	}
 }
 
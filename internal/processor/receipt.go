@@ -0,0 +1,131 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"bigbrother/internal/i18n"
+)
+
+// CheckedReceipts returns the beer vs bottles results of every receipt or
+// refund with the given number, one per register that issued it.
+func (r Report) CheckedReceipts(no string) []ReceiptReport {
+	var out []ReceiptReport
+	for _, list := range [][]ReceiptReport{r.Receipts, r.Refunds} {
+		for _, rec := range list {
+			if rec.ReceiptNo == no {
+				out = append(out, rec)
+			}
+		}
+	}
+	return out
+}
+
+func (r Report) checkedOn(register, no string) (ReceiptReport, bool) {
+	for _, list := range [][]ReceiptReport{r.Receipts, r.Refunds} {
+		for _, rec := range list {
+			if rec.ReceiptNo == no && rec.Register == register {
+				return rec, true
+			}
+		}
+	}
+	return ReceiptReport{}, false
+}
+
+// FormatReceipt renders the original rows of a receipt together with its
+// beer/bottle totals, status and any audit findings about it. When several
+// registers issued the number, each receipt is rendered. Labels are printed
+// with p; findings keep the language the audits ran in.
+func (r Report) FormatReceipt(no string, p i18n.Printer) (string, bool) {
+	no = strings.TrimSpace(no)
+	var cards []string
+	for _, rec := range r.Parsed {
+		if rec.No == no {
+			cards = append(cards, r.formatReceipt(rec, p))
+		}
+	}
+	if len(cards) == 0 {
+		return "", false
+	}
+	return strings.Join(cards, "\n\n"), true
+}
+
+func (r Report) formatReceipt(rec Receipt, p i18n.Printer) string {
+	var b strings.Builder
+	b.WriteString(p.Sprintf("Receipt %s", rec.No) + "\n")
+	if rec.IssuedAt != "" {
+		b.WriteString(p.Sprintf("Time: %s", rec.IssuedAt) + "\n")
+	}
+	if rec.Register != "" {
+		b.WriteString(p.Sprintf("Register: %s", rec.Register) + "\n")
+	}
+	if rec.Operator != "" {
+		b.WriteString(p.Sprintf("Operator: %s", rec.Operator) + "\n")
+	}
+	if rec.OriginalNo != "" {
+		b.WriteString(p.Sprintf("Refund of: %s", rec.OriginalNo) + "\n")
+	}
+
+	b.WriteString("\n" + p.Sprintf("Rows:") + "\n")
+	for _, line := range rec.Lines {
+		b.WriteString(fmt.Sprintf("  %d. %s / %s x%s\n", line.Row, line.Category, line.Product, line.Quantity))
+	}
+
+	b.WriteString("\n")
+	if rr, ok := r.checkedOn(rec.Register, rec.No); ok {
+		b.WriteString(p.Sprintf("Total beer: %s", formatLiters(rr.BeerML)) + "\n")
+		b.WriteString(p.Sprintf("Total bottles: %s", formatLiters(rr.BottleTotalML)) + "\n")
+		b.WriteString(p.Sprintf("Bottles: %s", formatBottleList(rr.BottleByML, rr.BottleOrder)) + "\n")
+		if len(rr.RefundedBy) > 0 {
+			b.WriteString(p.Sprintf("Refunded by: %s", strings.Join(rr.RefundedBy, ", ")) + "\n")
+		}
+		b.WriteString(p.Sprintf("Status: %s", receiptStatus(rr, p)) + "\n")
+		if rr.Resolution != "" {
+			b.WriteString(p.Sprintf("Resolved: %s", ResolutionTitle(rr.Resolution, p)) + "\n")
+		}
+	} else if _, ok := r.checkedOn(rec.Register, rec.OriginalNo); ok && rec.OriginalNo != "" {
+		b.WriteString(p.Sprintf("Status: netted into receipt %s", rec.OriginalNo) + "\n")
+	} else {
+		b.WriteString(p.Sprintf("Status: no beer or bottles") + "\n")
+	}
+	if rec.Duplicate != nil {
+		b.WriteString(p.Sprintf("Duplicate: %s", duplicateText(rec, false, p)) + "\n")
+	}
+
+	var findings []Finding
+	for _, res := range r.Audits {
+		for _, f := range res.Findings {
+			if f.ReceiptNo == rec.No && f.Register == rec.Register {
+				findings = append(findings, f)
+			}
+		}
+	}
+	if len(findings) > 0 {
+		b.WriteString("\n" + p.Sprintf("Findings:") + "\n")
+		for _, f := range findings {
+			b.WriteString(fmt.Sprintf("  [%s] %s\n", f.Audit, f.Message))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// receiptStatus explains in one line whether the receipt is fine and, if
+// not, which way the difference goes.
+func receiptStatus(rec ReceiptReport, p i18n.Printer) string {
+	switch {
+	case rec.Flag != "":
+		return p.Sprintf("suspicious, %s", flagText(rec.Flag, p))
+	case rec.Match && rec.DiffML != 0:
+		return p.Sprintf("OK, within tolerance (%s)", formatDiff(rec.DiffML))
+	case rec.Match:
+		return p.Sprintf("OK, beer and bottles match")
+	case rec.BottleTotalML == 0:
+		return p.Sprintf("mismatch, %s of beer sold without bottles", formatLiters(rec.BeerML))
+	case rec.BeerML == 0:
+		return p.Sprintf("mismatch, %s of bottles sold without beer", formatLiters(rec.BottleTotalML))
+	case rec.DiffML > 0:
+		return p.Sprintf("mismatch, bottles hold %s more than the beer sold", formatLiters(rec.DiffML))
+	default:
+		return p.Sprintf("mismatch, %s of beer did not fit into the bottles sold", formatLiters(-rec.DiffML))
+	}
+}
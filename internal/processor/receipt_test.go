@@ -0,0 +1,73 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestFormatReceipt(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		headerOriginal,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "2", ""},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "1", ""},
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "-1", "R1"},
+		{"R3", "Nealko nápoje", "Kofola", "2026-02-06 12:00:00", "1", ""},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	en := i18n.For(i18n.English)
+	text, ok := report.FormatReceipt("R1", en)
+	if !ok {
+		t.Fatal("expected R1 to be found")
+	}
+	for _, want := range []string{"PET láhve / Láhev 1 l x1", "Refunded by: R2", "Status: suspicious, refund R2: beer refunded without bottles", "[bottles]"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	if text, _ := report.FormatReceipt("R2", en); !strings.Contains(text, "Status: netted into receipt R1") {
+		t.Fatalf("unexpected refund text:\n%s", text)
+	}
+	if text, _ := report.FormatReceipt("R3", en); !strings.Contains(text, "Status: no beer or bottles") {
+		t.Fatalf("unexpected non-beer text:\n%s", text)
+	}
+	if _, ok := report.FormatReceipt("R9", en); ok {
+		t.Fatal("expected R9 to be missing")
+	}
+
+	text, _ = report.FormatReceipt("R1", i18n.For(i18n.Czech))
+	if !strings.Contains(text, "Stav: podezřelé, vratka R2: pivo vráceno bez lahví") {
+		t.Fatalf("expected a Czech status in:\n%s", text)
+	}
+}
+
+func TestReceiptStatus(t *testing.T) {
+	cases := []struct {
+		rec  ReceiptReport
+		want string
+	}{
+		{ReceiptReport{BeerML: 500, BottleTotalML: 500, Match: true}, "OK"},
+		{ReceiptReport{BeerML: 500}, "0.50L of beer sold without bottles"},
+		{ReceiptReport{BeerML: 1500, BottleTotalML: 1000, DiffML: -500}, "0.50L of beer did not fit"},
+		{ReceiptReport{BeerML: 500, BottleTotalML: 1000, DiffML: 500}, "0.50L more than the beer sold"},
+	}
+	for _, tc := range cases {
+		if got := receiptStatus(tc.rec, i18n.For(i18n.English)); !strings.Contains(got, tc.want) {
+			t.Fatalf("expected %q in %q", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+// Reasons a mismatch can be resolved with.
+const (
+	ResolutionExplained  = "explained"
+	ResolutionStaffError = "staff_error"
+	ResolutionFixedInPOS = "fixed_in_pos"
+)
+
+// ResolutionReasons lists the valid reasons in the order they are offered.
+var ResolutionReasons = []string{ResolutionExplained, ResolutionStaffError, ResolutionFixedInPOS}
+
+// ResolutionTitle returns the label shown for a resolution reason.
+func ResolutionTitle(reason string, p i18n.Printer) string {
+	switch reason {
+	case ResolutionExplained:
+		return p.Sprintf("explained")
+	case ResolutionStaffError:
+		return p.Sprintf("staff error")
+	case ResolutionFixedInPOS:
+		return p.Sprintf("fixed in POS")
+	default:
+		return reason
+	}
+}
+
+// ReceiptRef identifies a receipt across reports. Registers number their
+// receipts independently and POS systems reuse numbers, so the register and
+// the day the receipt was issued are part of it.
+type ReceiptRef struct {
+	Register string `json:"register,omitempty"`
+	No       string `json:"receipt_no"`
+	Date     string `json:"date,omitempty"`
+}
+
+// Ref returns the reference of the receipt. Date is empty when the file
+// has no parsable issue times.
+func (r ReceiptReport) Ref() ReceiptRef {
+	ref := ReceiptRef{Register: r.Register, No: r.ReceiptNo}
+	if !r.Issued.IsZero() {
+		ref.Date = r.Issued.Format(time.DateOnly)
+	}
+	return ref
+}
+
+// Resolutions tells the reason a receipt was resolved with, or "" when it
+// was not.
+type Resolutions interface {
+	Reason(rec ReceiptReport) string
+}
+
+// ResolutionMap holds resolution reasons by receipt.
+type ResolutionMap map[ReceiptRef]string
+
+func (m ResolutionMap) Reason(rec ReceiptReport) string {
+	return m[rec.Ref()]
+}
+
+// OpenMismatch reports whether the receipt is a mismatch nobody resolved yet.
+func (r ReceiptReport) OpenMismatch() bool {
+	return !r.Match && r.Resolution == ""
+}
+
+// chargedToOperator reports whether the mismatch counts against the
+// operator. Mismatches resolved as explained or fixed in the POS were not
+// the operator's fault; staff errors still are.
+func (r ReceiptReport) chargedToOperator() bool {
+	return !r.Match && (r.Resolution == "" || r.Resolution == ResolutionStaffError)
+}
+
+// ApplyResolutions marks resolved receipts and hides them from the mismatch
+// counts and the beer vs bottles findings.
+func (r *Report) ApplyResolutions(resolved Resolutions) {
+	if resolved == nil {
+		return
+	}
+
+	r.MismatchCount = 0
+	r.SuspiciousRefunds = 0
+	done := make(map[receiptID]bool)
+	for _, list := range [][]ReceiptReport{r.Receipts, r.Refunds} {
+		for i := range list {
+			list[i].Resolution = resolved.Reason(list[i])
+			if list[i].Resolution != "" {
+				done[receiptID{register: list[i].Register, no: list[i].ReceiptNo}] = true
+				continue
+			}
+			if list[i].Flag != "" {
+				r.SuspiciousRefunds++
+			}
+			if !list[i].Refund && !list[i].Match {
+				r.MismatchCount++
+			}
+		}
+	}
+
+	for i := range r.Audits {
+		if r.Audits[i].Name != AuditBottles {
+			continue
+		}
+		kept := r.Audits[i].Findings[:0]
+		for _, f := range r.Audits[i].Findings {
+			if !done[receiptID{register: f.Register, no: f.ReceiptNo}] {
+				kept = append(kept, f)
+			}
+		}
+		r.Audits[i].Findings = kept
+	}
+
+	r.Operators = OperatorStats(r.Receipts)
+}
@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestReport_ApplyResolutions(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		"Pokladník",
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1", "Jana"},
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "1", "Jana"},
+		{"R3", "Pivovar Test", "Beer", "2026-02-06 12:00:00", "1", "Jana"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	day := "2026-02-06"
+	report.ApplyResolutions(ResolutionMap{
+		{No: "R1", Date: day}:          ResolutionExplained,
+		{No: "R2", Date: day}:          ResolutionStaffError,
+		{No: "R3", Date: "2026-01-02"}: ResolutionExplained,
+	})
+
+	if report.MismatchCount != 1 {
+		t.Fatalf("expected 1 open mismatch, got: %d", report.MismatchCount)
+	}
+	for _, res := range report.Audits {
+		if res.Name != AuditBottles {
+			continue
+		}
+		if len(res.Findings) != 1 || res.Findings[0].ReceiptNo != "R3" {
+			t.Fatalf("expected only R3 in findings, got: %+v", res.Findings)
+		}
+	}
+	// Staff errors still count against the operator, explained mismatches do not.
+	if len(report.Operators) != 1 || report.Operators[0].Mismatches != 2 {
+		t.Fatalf("unexpected operator stats: %+v", report.Operators)
+	}
+}
+
+func TestReport_ApplyTolerance(t *testing.T) {
+	headers := []string{headerReceipt, headerCategory, headerProduct, headerIssuedAt, headerQuantity}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "0.96"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "1"},
+		{"R2", "Pivovar Test", "Beer", "2026-02-06 11:00:00", "0.8"},
+		{"R2", "PET láhve", "Láhev 1 l", "2026-02-06 11:00:00", "1"},
+	}
+	report, err := ProcessXLSX(writeXLSX(t, headers, rows))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.MismatchCount != 2 {
+		t.Fatalf("expected 2 mismatches without tolerance, got: %d", report.MismatchCount)
+	}
+
+	report.ApplyTolerance(50)
+	report.ApplyAudits(defaultRegistry, DefaultAudits)
+	if report.MismatchCount != 1 || !report.Receipts[0].Match || report.Receipts[1].Match {
+		t.Fatalf("expected only R2 to stay a mismatch, got: %+v", report.Receipts)
+	}
+	if status := receiptStatus(report.Receipts[0], i18n.For(i18n.English)); status != "OK, within tolerance (+0.04L)" {
+		t.Fatalf("unexpected status: %q", status)
+	}
+	if len(report.Audits) == 0 || report.Audits[0].Name != AuditBottles {
+		t.Fatalf("expected the bottles audit first, got: %+v", report.Audits)
+	}
+	findings := report.Audits[0].Findings
+	if len(findings) != 1 || findings[0].ReceiptNo != "R2" {
+		t.Fatalf("expected a finding for R2 only, got: %+v", findings)
+	}
+}
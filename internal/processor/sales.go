@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const topProducts = 5
+
+// BeerSale is the beer volume sold of one product within a category.
+type BeerSale struct {
+	Category string
+	Product  string
+	ML       int64
+}
+
+// ProductStat is the beer volume of one product and its share of all beer sold.
+type ProductStat struct {
+	Category string  `json:"category"`
+	Product  string  `json:"product"`
+	ML       int64   `json:"ml"`
+	Share    float64 `json:"share"`
+}
+
+// CategoryStat is the beer volume of one category, usually a brewery.
+type CategoryStat struct {
+	Category string  `json:"category"`
+	ML       int64   `json:"ml"`
+	Share    float64 `json:"share"`
+}
+
+// BeerSales sums the beer rows of every parsed receipt per category and
+// product, in the order they first appear. Refund rows reduce the totals.
+func (r Report) BeerSales() []BeerSale {
+	type key struct{ category, product string }
+
+	index := make(map[key]int)
+	var sales []BeerSale
+	for _, rec := range r.Parsed {
+		for _, line := range rec.Lines {
+			if line.BeerML == 0 {
+				continue
+			}
+			k := key{line.Category, line.Product}
+			i, ok := index[k]
+			if !ok {
+				i = len(sales)
+				index[k] = i
+				sales = append(sales, BeerSale{Category: line.Category, Product: line.Product})
+			}
+			sales[i].ML += line.BeerML
+		}
+	}
+	return sales
+}
+
+// BottleSales sums sold PET bottles per size in ml. Refund rows reduce the totals.
+func (r Report) BottleSales() map[int64]int64 {
+	sold := make(map[int64]int64)
+	for _, rec := range r.Parsed {
+		for _, line := range rec.Lines {
+			if line.BottleML > 0 {
+				sold[line.BottleML] += line.BottleCount
+			}
+		}
+	}
+	return sold
+}
+
+// collectStats fills the per-product and per-category totals, largest first.
+func (r *Report) collectStats() {
+	sales := r.BeerSales()
+
+	r.BeerTotalML = 0
+	for _, sale := range sales {
+		r.BeerTotalML += sale.ML
+	}
+
+	r.Products = r.Products[:0]
+	categories := make(map[string]int)
+	r.Categories = r.Categories[:0]
+	for _, sale := range sales {
+		if sale.ML <= 0 {
+			continue
+		}
+		r.Products = append(r.Products, ProductStat{
+			Category: sale.Category,
+			Product:  sale.Product,
+			ML:       sale.ML,
+			Share:    share(sale.ML, r.BeerTotalML),
+		})
+		i, ok := categories[sale.Category]
+		if !ok {
+			i = len(r.Categories)
+			categories[sale.Category] = i
+			r.Categories = append(r.Categories, CategoryStat{Category: sale.Category})
+		}
+		r.Categories[i].ML += sale.ML
+	}
+	for i := range r.Categories {
+		r.Categories[i].Share = share(r.Categories[i].ML, r.BeerTotalML)
+	}
+
+	sort.SliceStable(r.Products, func(i, j int) bool { return r.Products[i].ML > r.Products[j].ML })
+	sort.SliceStable(r.Categories, func(i, j int) bool { return r.Categories[i].ML > r.Categories[j].ML })
+}
+
+func share(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func (r Report) formatWhatSold() string {
+	if len(r.Products) == 0 {
+		return ""
+	}
+
+	pr := r.printer()
+	var b strings.Builder
+	b.WriteString(pr.Sprintf("\n===== What sold (%s) =====\n", formatLiters(r.BeerTotalML)))
+	for i, p := range r.Products {
+		if i == topProducts {
+			more := len(r.Products) - i
+			b.WriteString(pr.Plural(more, "...and %d more product\n", "...and %d more products\n", more))
+			break
+		}
+		b.WriteString(fmt.Sprintf("%d. %s / %s: %s (%.0f%%)\n", i+1, p.Category, p.Product, formatLiters(p.ML), p.Share*100))
+	}
+	if len(r.Categories) > 1 {
+		parts := make([]string, 0, topProducts+1)
+		for i, c := range r.Categories {
+			if i == topProducts {
+				parts = append(parts, pr.Sprintf("%d more", len(r.Categories)-i))
+				break
+			}
+			parts = append(parts, fmt.Sprintf("%s %s (%.0f%%)", c.Category, formatLiters(c.ML), c.Share*100))
+		}
+		b.WriteString(pr.Sprintf("By category: %s\n", strings.Join(parts, ", ")))
+	}
+	return b.String()
+}
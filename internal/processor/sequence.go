@@ -0,0 +1,196 @@
+package processor
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bigbrother/internal/i18n"
+)
+
+// issuedAtLayouts are day-first and ISO layouts only. Month-first US dates
+// would read a Czech 03-04 as March 4.
+var issuedAtLayouts = []string{
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"2.1.2006 15:04:05",
+	"2.1.2006 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+}
+
+// sequenceResult describes gaps, duplicates and ordering problems in the
+// receipt numbers of a single file, checked per register.
+type sequenceResult struct {
+	gaps       []receiptGap
+	duplicates []Receipt
+	outOfOrder []outOfOrderReceipt
+}
+
+type receiptGap struct {
+	register string
+	after    int64
+	before   int64
+}
+
+type outOfOrderReceipt struct {
+	register  string
+	receiptNo string
+	issuedAt  string
+	previous  string
+}
+
+type sequenceEntry struct {
+	number  int64
+	receipt Receipt
+}
+
+type sequenceAudit struct{}
+
+// NewSequenceAudit checks receipt numbers for gaps, duplicates and numbering
+// that goes backwards in time on the same register.
+func NewSequenceAudit() Audit {
+	return sequenceAudit{}
+}
+
+func (sequenceAudit) Name() string  { return AuditSequence }
+func (sequenceAudit) Title() string { return "Receipt sequence" }
+
+func (sequenceAudit) Run(receipts []Receipt, p i18n.Printer) []Finding {
+	res := auditSequence(receipts)
+
+	var findings []Finding
+	for _, gap := range res.gaps {
+		msg := p.Sprintf("%d-%d missing", gap.after+1, gap.before-1)
+		if gap.before-gap.after == 2 {
+			msg = p.Sprintf("%d missing", gap.after+1)
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Subject:  registerLabel(gap.register, p),
+			Message:  msg,
+		})
+	}
+	for _, rec := range res.duplicates {
+		findings = append(findings, Finding{
+			Severity:  SeverityWarning,
+			Subject:   registerLabel(rec.Register, p),
+			ReceiptNo: rec.No,
+			Register:  rec.Register,
+			Message:   duplicateText(rec, true, p),
+		})
+	}
+	for _, rec := range res.outOfOrder {
+		findings = append(findings, Finding{
+			Severity:  SeverityInfo,
+			Subject:   registerLabel(rec.register, p),
+			ReceiptNo: rec.receiptNo,
+			Register:  rec.register,
+			Message:   p.Sprintf("%s issued %s after %s", rec.receiptNo, rec.issuedAt, rec.previous),
+		})
+	}
+	return findings
+}
+
+func auditSequence(receipts []Receipt) sequenceResult {
+	var audit sequenceResult
+	byRegister := make(map[string][]sequenceEntry)
+	var registers []string
+
+	for _, rec := range receipts {
+		if rec.Duplicate != nil {
+			audit.duplicates = append(audit.duplicates, rec)
+		}
+		number, err := strconv.ParseInt(rec.No, 10, 64)
+		if err != nil {
+			continue
+		}
+		if _, ok := byRegister[rec.Register]; !ok {
+			registers = append(registers, rec.Register)
+		}
+		byRegister[rec.Register] = append(byRegister[rec.Register], sequenceEntry{number: number, receipt: rec})
+	}
+
+	sort.Strings(registers)
+	for _, register := range registers {
+		entries := byRegister[register]
+
+		sort.Slice(entries, func(i, j int) bool { return entries[i].number < entries[j].number })
+		for i := 1; i < len(entries); i++ {
+			if entries[i].number-entries[i-1].number > 1 {
+				audit.gaps = append(audit.gaps, receiptGap{
+					register: register,
+					after:    entries[i-1].number,
+					before:   entries[i].number,
+				})
+			}
+		}
+
+		if !allIssued(entries) {
+			continue
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].receipt.Issued.Before(entries[j].receipt.Issued) })
+		highest := entries[0]
+		for _, entry := range entries[1:] {
+			if entry.number < highest.number {
+				audit.outOfOrder = append(audit.outOfOrder, outOfOrderReceipt{
+					register:  register,
+					receiptNo: entry.receipt.No,
+					issuedAt:  entry.receipt.IssuedAt,
+					previous:  highest.receipt.No,
+				})
+				continue
+			}
+			highest = entry
+		}
+	}
+
+	return audit
+}
+
+func allIssued(entries []sequenceEntry) bool {
+	for _, entry := range entries {
+		if entry.receipt.Issued.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func parseIssuedAt(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range issuedAtLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// duplicateText explains why the number of rec looks reused, starting with
+// the number when withNo is set.
+func duplicateText(rec Receipt, withNo bool, p i18n.Printer) string {
+	dup := rec.Duplicate
+	switch {
+	case dup.Kind == DuplicateIssuedTwice && withNo:
+		return p.Sprintf("%s issued at %s and %s (row %d)", rec.No, rec.IssuedAt, dup.IssuedAt, dup.Row)
+	case dup.Kind == DuplicateIssuedTwice:
+		return p.Sprintf("issued at %s and %s (row %d)", rec.IssuedAt, dup.IssuedAt, dup.Row)
+	case withNo:
+		return p.Sprintf("%s reused on row %d", rec.No, dup.Row)
+	default:
+		return p.Sprintf("reused on row %d", dup.Row)
+	}
+}
+
+func registerLabel(register string, p i18n.Printer) string {
+	if register == "" {
+		return p.Sprintf("Register -")
+	}
+	return register
+}
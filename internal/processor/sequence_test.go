@@ -0,0 +1,123 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestProcessXLSX_SequenceAudit(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerRegister,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"100", "Pokladna 1", "Nealko", "Kofola", "06.02.2026 10:00:00", "1"},
+		{"101", "Pokladna 1", "Nealko", "Kofola", "06.02.2026 10:05:00", "1"},
+		{"104", "Pokladna 1", "Nealko", "Kofola", "06.02.2026 10:03:00", "1"},
+		{"105", "Pokladna 1", "Nealko", "Kofola", "06.02.2026 10:10:00", "1"},
+		{"105", "Pokladna 1", "Nealko", "Kofola", "06.02.2026 11:10:00", "1"},
+		{"500", "Pokladna 2", "Nealko", "Kofola", "06.02.2026 10:00:00", "1"},
+		{"501", "Pokladna 2", "Nealko", "Kofola", "06.02.2026 10:01:00", "1"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	findings := sequenceFindings(t, report)
+	if len(findings) != 3 {
+		t.Fatalf("expected a gap, a duplicate and a receipt out of order, got: %+v", findings)
+	}
+	if gap := findings[0]; gap.Subject != "Pokladna 1" || gap.Message != "102-103 missing" {
+		t.Fatalf("expected a gap of 2 on Pokladna 1, got: %+v", gap)
+	}
+	if dup := findings[1]; dup.ReceiptNo != "105" || dup.Message != "105 issued at 06.02.2026 10:10:00 and 06.02.2026 11:10:00 (row 6)" {
+		t.Fatalf("expected duplicate 105, got: %+v", dup)
+	}
+	if late := findings[2]; late.ReceiptNo != "101" || late.Message != "101 issued 06.02.2026 10:05:00 after 104" {
+		t.Fatalf("expected 101 out of order after 104, got: %+v", late)
+	}
+	if !strings.Contains(report.FormatText(), "Pokladna 1: 102-103 missing") {
+		t.Fatalf("expected gap in text, got: %s", report.FormatText())
+	}
+
+	report.Lang = i18n.Czech
+	report.ApplyAudits(DefaultRegistry(), []string{AuditSequence})
+	if text := report.FormatText(); !strings.Contains(text, "105 vystavena 06.02.2026 10:10:00 a 06.02.2026 11:10:00 (řádek 6)") {
+		t.Fatalf("expected a translated duplicate, got: %s", text)
+	}
+}
+
+func TestProcessXLSX_SequencePerRegister(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerRegister,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"100", "Pokladna 1", "Pivovar Test", "Beer", "06.02.2026 10:00:00", "1"},
+		{"100", "Pokladna 1", "PET láhve", "Láhev 1 l", "06.02.2026 10:00:00", "1"},
+		{"100", "Pokladna 2", "Pivovar Test", "Beer", "06.02.2026 10:02:00", "2"},
+		{"101", "Pokladna 1", "Nealko", "Kofola", "06.02.2026 10:05:00", "1"},
+		{"101", "Pokladna 2", "Nealko", "Kofola", "06.02.2026 10:06:00", "1"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Parsed) != 4 {
+		t.Fatalf("expected a receipt per register and number, got: %+v", report.Parsed)
+	}
+	if findings := sequenceFindings(t, report); len(findings) != 0 {
+		t.Fatalf("expected two clean sequences, got: %+v", findings)
+	}
+	if report.TotalReceipts != 2 || report.MismatchCount != 1 {
+		t.Fatalf("expected receipt 100 checked on both registers, got: %+v", report)
+	}
+	if mismatch := report.Receipts[1]; mismatch.Register != "Pokladna 2" || mismatch.BeerML != 2000 {
+		t.Fatalf("expected the mismatch on Pokladna 2, got: %+v", mismatch)
+	}
+	text, ok := report.FormatReceipt("100", i18n.For(i18n.English))
+	if !ok || strings.Count(text, "Receipt 100\n") != 2 || !strings.Contains(text, "Register: Pokladna 2") {
+		t.Fatalf("expected both receipts 100, got:\n%s", text)
+	}
+}
+
+// sequenceFindings returns the findings of the receipt sequence audit.
+func sequenceFindings(t *testing.T, report Report) []Finding {
+	t.Helper()
+	for _, res := range report.Audits {
+		if res.Name == AuditSequence {
+			return res.Findings
+		}
+	}
+	t.Fatalf("expected the sequence audit to run, got: %+v", report.Audits)
+	return nil
+}
+
+func TestParseIssuedAt(t *testing.T) {
+	for _, raw := range []string{"06.02.2026 21:58:12", "2026-02-06 21:58:12"} {
+		ts, ok := parseIssuedAt(raw)
+		if !ok || ts.Day() != 6 || ts.Hour() != 21 || ts.Minute() != 58 {
+			t.Fatalf("unexpected parse of %q: %v %v", raw, ts, ok)
+		}
+	}
+	// Day and month are ambiguous in short US dates; Czech exports put the day first.
+	for _, raw := range []string{"yesterday", "03-04-25 10:00", "3/4/25 10:00"} {
+		if ts, ok := parseIssuedAt(raw); ok {
+			t.Fatalf("expected %q to be unparsable, got %v", raw, ts)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const unassignedShift = "unassigned"
+
+// Shift is a named time range that receipts are assigned to by issue time.
+type Shift struct {
+	Name  string
+	Range TimeRange
+}
+
+func (s Shift) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if s.Range.overnight() {
+		return minute >= s.Range.Start || minute < s.Range.End
+	}
+	return minute >= s.Range.Start && minute < s.Range.End
+}
+
+// ShiftStat summarizes the checked receipts of one shift.
+type ShiftStat struct {
+	Name       string `json:"name"`
+	Hours      string `json:"hours,omitempty"`
+	Receipts   int    `json:"receipts"`
+	BeerML     int64  `json:"beer_ml"`
+	Mismatches int    `json:"mismatches"`
+}
+
+// ParseShifts parses "morning=06:00-14:00;evening=14:00-23:00". The first
+// matching shift wins when ranges overlap.
+func ParseShifts(raw string) ([]Shift, error) {
+	var shifts []Shift
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, hours, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid shift: %s", part)
+		}
+		ranges, err := parseRanges(hours)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shift %s: %w", name, err)
+		}
+		if len(ranges) != 1 {
+			return nil, fmt.Errorf("shift %s needs exactly one time range", name)
+		}
+		shifts = append(shifts, Shift{Name: name, Range: ranges[0]})
+	}
+	return shifts, nil
+}
+
+// ShiftOf returns the name of the shift t falls into, or "" when none matches.
+func ShiftOf(shifts []Shift, t time.Time) string {
+	for _, s := range shifts {
+		if s.contains(t) {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// ApplyShifts assigns every checked receipt to a shift by its issue time.
+// Receipts without a parsable time or outside all shifts are counted as unassigned.
+func (r *Report) ApplyShifts(shifts []Shift) {
+	r.Shifts = nil
+	if len(shifts) == 0 {
+		return
+	}
+
+	index := make(map[string]int, len(shifts)+1)
+	for _, s := range shifts {
+		index[s.Name] = len(r.Shifts)
+		r.Shifts = append(r.Shifts, ShiftStat{Name: s.Name, Hours: s.Range.String()})
+	}
+
+	for _, rec := range r.Receipts {
+		name := unassignedShift
+		if issued, ok := parseIssuedAt(rec.IssuedAt); ok {
+			if shift := ShiftOf(shifts, issued); shift != "" {
+				name = shift
+			}
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(r.Shifts)
+			index[name] = i
+			r.Shifts = append(r.Shifts, ShiftStat{Name: name})
+		}
+		r.Shifts[i].Receipts++
+		r.Shifts[i].BeerML += rec.BeerML
+		if rec.OpenMismatch() {
+			r.Shifts[i].Mismatches++
+		}
+	}
+}
+
+func (r Report) formatShifts() string {
+	if len(r.Shifts) == 0 {
+		return ""
+	}
+
+	p := r.printer()
+	var b strings.Builder
+	b.WriteString(p.Sprintf("\n===== Shifts =====\n"))
+	for _, s := range r.Shifts {
+		name := s.Name
+		if s.Hours != "" {
+			name += " (" + s.Hours + ")"
+		}
+		b.WriteString(fmt.Sprintf("%s: %s, %s, %s\n", name, receiptCount(p, s.Receipts), formatLiters(s.BeerML), mismatchCount(p, s.Mismatches)))
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReport_ApplyShifts(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "06.02.2026 09:00:00", "1"},
+		{"R1", "PET láhve", "Láhev 1 l", "06.02.2026 09:00:00", "1"},
+		{"R2", "Pivovar Test", "Beer", "06.02.2026 18:00:00", "2"},
+		{"R2", "PET láhve", "Láhev 1 l", "06.02.2026 18:00:00", "1"},
+		{"R3", "Pivovar Test", "Beer", "07.02.2026 00:30:00", "1"},
+		{"R4", "Pivovar Test", "Beer", "07.02.2026 04:00:00", "0,5"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shifts, err := ParseShifts("morning=06:00-14:00; evening=14:00-01:00")
+	if err != nil {
+		t.Fatalf("parse shifts: %v", err)
+	}
+	report.ApplyShifts(shifts)
+
+	if len(report.Shifts) != 3 {
+		t.Fatalf("expected morning, evening and unassigned, got: %+v", report.Shifts)
+	}
+	morning, evening, other := report.Shifts[0], report.Shifts[1], report.Shifts[2]
+	if morning.Receipts != 1 || morning.Mismatches != 0 || morning.BeerML != 1000 {
+		t.Fatalf("unexpected morning: %+v", morning)
+	}
+	if evening.Receipts != 2 || evening.Mismatches != 2 || evening.BeerML != 3000 {
+		t.Fatalf("unexpected evening: %+v", evening)
+	}
+	if other.Name != unassignedShift || other.Receipts != 1 {
+		t.Fatalf("unexpected unassigned: %+v", other)
+	}
+	if !strings.Contains(report.FormatText(), "evening (14:00-01:00): 2 receipts, 3.00L, 2 mismatches") {
+		t.Fatalf("expected shift line in text, got: %s", report.FormatText())
+	}
+}
+
+func TestParseShifts_Invalid(t *testing.T) {
+	if _, err := ParseShifts("morning"); err == nil {
+		t.Fatal("expected error for shift without hours")
+	}
+	if _, err := ParseShifts("split=06:00-10:00,12:00-14:00"); err == nil {
+		t.Fatal("expected error for shift with two ranges")
+	}
+}
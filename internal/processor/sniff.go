@@ -0,0 +1,161 @@
+package processor
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Supported input formats, as detected from the file content.
+const (
+	FormatXLSX = "xlsx"
+	FormatCSV  = "csv"
+)
+
+// Limits on what a single upload may expand to. They are variables so tests
+// can lower them.
+var (
+	maxXLSXUncompressed int64 = 200 << 20
+	maxXLSXEntries            = 1000
+	maxRows                   = 200000
+	maxColumns                = 200
+)
+
+const sniffBytes = 8 << 10
+
+const odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+// RejectedError is returned for files that are refused before or during
+// processing. Reason is meant to be shown to the uploader.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "file rejected: " + e.Reason
+}
+
+func reject(format string, args ...any) error {
+	return &RejectedError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// IsRejected reports whether err is a RejectedError and returns its reason.
+func IsRejected(err error) (string, bool) {
+	var rejected *RejectedError
+	if errors.As(err, &rejected) {
+		return rejected.Reason, true
+	}
+	return "", false
+}
+
+// DetectFormat looks at the content of the file at path and returns
+// FormatXLSX or FormatCSV. The file name is not consulted. Anything else,
+// including ODS, legacy .xls and oversized archives, is a RejectedError.
+func DetectFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffBytes)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	head = head[:n]
+
+	switch {
+	case len(head) == 0:
+		return "", reject("the file is empty")
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return FormatXLSX, checkXLSX(f)
+	case bytes.HasPrefix(head, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")):
+		return "", reject("this is a legacy Excel (.xls) file, please save it as .xlsx or .csv")
+	case bytes.HasPrefix(head, []byte("\xFF\xFE")), bytes.HasPrefix(head, []byte("\xFE\xFF")):
+		// UTF-16 text is full of NUL bytes; tell it apart from binary files.
+		return "", reject("this is a UTF-16 text file, please export the CSV as UTF-8")
+	case isBinary(head):
+		return "", reject("this is not a spreadsheet or a text CSV file")
+	default:
+		return FormatCSV, nil
+	}
+}
+
+// checkXLSX verifies that the zip archive f is an Excel workbook and that
+// unpacking it stays within the limits. The sizes come from the central
+// directory, so nothing is decompressed here.
+func checkXLSX(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat file: %w", err)
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return reject("the file looks like a zip archive but cannot be opened")
+	}
+	if len(zr.File) > maxXLSXEntries {
+		return reject("the workbook has %d parts, the limit is %d", len(zr.File), maxXLSXEntries)
+	}
+
+	var total uint64
+	workbook := false
+	for _, entry := range zr.File {
+		total += entry.UncompressedSize64
+		if total > uint64(maxXLSXUncompressed) {
+			return reject("the workbook unpacks to more than %d MB", maxXLSXUncompressed>>20)
+		}
+		switch entry.Name {
+		case "xl/workbook.xml":
+			workbook = true
+		case "mimetype":
+			if isODS(entry) {
+				return reject("OpenDocument spreadsheets (.ods) are not supported, please save it as .xlsx or .csv")
+			}
+		}
+	}
+	if !workbook {
+		return reject("the zip archive is not an Excel workbook")
+	}
+	return nil
+}
+
+func isODS(entry *zip.File) bool {
+	r, err := entry.Open()
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, 128))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(string(data), odsMimeType)
+}
+
+// isBinary reports whether head contains NUL bytes or control characters a
+// text export would not have. Invalid UTF-8 is allowed, since POS exports are
+// often in a legacy code page.
+func isBinary(head []byte) bool {
+	for _, c := range head {
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' && c != '\f' {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRowLimits rejects rows past maxRows and rows wider than maxColumns.
+func checkRowLimits(row []string, rowNum int) error {
+	if rowNum > maxRows {
+		return reject("the file has more than %d rows", maxRows)
+	}
+	if len(row) > maxColumns {
+		return reject("row %d has %d columns, the limit is %d", rowNum, len(row), maxColumns)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeZip(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "upload.xlsx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, data := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatalf("write entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close file: %v", err)
+	}
+	return path
+}
+
+func TestDetectFormat_ByContent(t *testing.T) {
+	xlsx := writeXLSX(t, []string{headerReceipt}, [][]string{{"R1"}})
+	renamed := filepath.Join(t.TempDir(), "export.csv")
+	if err := os.Rename(xlsx, renamed); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if format, err := DetectFormat(renamed); err != nil || format != FormatXLSX {
+		t.Fatalf("expected xlsx, got %q (%v)", format, err)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "export.xlsx")
+	// Windows-1250 "Číslo" is not valid UTF-8 but still a text export.
+	if err := os.WriteFile(csvPath, []byte("\xc8\xedslo;Produkt\r\nR1;Beer\r\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if format, err := DetectFormat(csvPath); err != nil || format != FormatCSV {
+		t.Fatalf("expected csv, got %q (%v)", format, err)
+	}
+}
+
+func TestDetectFormat_Rejects(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return path
+	}
+
+	cases := map[string]struct {
+		path string
+		want string
+	}{
+		"empty":  {write("empty.csv", ""), "empty"},
+		"xls":    {write("old.xlsx", "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1rest"), ".xls"},
+		"binary": {write("photo.csv", "\x89PNG\r\n\x1a\n\x00\x00"), "not a spreadsheet"},
+		"utf16":  {write("utf16.csv", "\xFF\xFEa\x00;\x00b\x00\r\x00\n\x00"), "UTF-16"},
+		"ods":    {writeZip(t, map[string]string{"mimetype": odsMimeType, "content.xml": "<x/>"}), ".ods"},
+		"zip":    {writeZip(t, map[string]string{"readme.txt": "hi"}), "not an Excel workbook"},
+	}
+	for name, tc := range cases {
+		_, err := DetectFormat(tc.path)
+		reason, ok := IsRejected(err)
+		if !ok || !strings.Contains(reason, tc.want) {
+			t.Fatalf("%s: expected rejection mentioning %q, got: %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestDetectFormat_ZipLimits(t *testing.T) {
+	defer func(size int64, entries int) { maxXLSXUncompressed, maxXLSXEntries = size, entries }(maxXLSXUncompressed, maxXLSXEntries)
+	maxXLSXUncompressed, maxXLSXEntries = 1<<20, 3
+
+	bomb := writeZip(t, map[string]string{"xl/workbook.xml": "<w/>", "xl/worksheets/sheet1.xml": strings.Repeat("0", 2<<20)})
+	if reason, ok := IsRejected(errOf(DetectFormat(bomb))); !ok || !strings.Contains(reason, "unpacks to more than 1 MB") {
+		t.Fatalf("expected size rejection, got: %q", reason)
+	}
+
+	entries := map[string]string{"xl/workbook.xml": "<w/>"}
+	for i := range 3 {
+		entries[fmt.Sprintf("xl/media/image%d.png", i)] = "x"
+	}
+	if reason, ok := IsRejected(errOf(DetectFormat(writeZip(t, entries)))); !ok || !strings.Contains(reason, "4 parts") {
+		t.Fatalf("expected entry count rejection, got: %q", reason)
+	}
+}
+
+func TestProcessFile_RowAndColumnCaps(t *testing.T) {
+	defer func(rows, cols int) { maxRows, maxColumns = rows, cols }(maxRows, maxColumns)
+	maxRows, maxColumns = 3, 5
+
+	headers := []string{headerReceipt, headerCategory, headerProduct, headerIssuedAt, headerQuantity}
+	row := []string{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1"}
+	path := writeXLSX(t, headers, [][]string{row, row, row})
+	if reason, ok := IsRejected(errOf(ProcessFile(path))); !ok || !strings.Contains(reason, "more than 3 rows") {
+		t.Fatalf("expected row cap rejection, got: %q", reason)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "wide.csv")
+	data := strings.Join(headers, ";") + "\n" + strings.Join(append(row, "extra"), ";") + "\n"
+	if err := os.WriteFile(csvPath, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if reason, ok := IsRejected(errOf(ProcessFile(csvPath))); !ok || !strings.Contains(reason, "row 2 has 6 columns") {
+		t.Fatalf("expected column cap rejection, got: %q", reason)
+	}
+}
+
+func errOf[T any](_ T, err error) error {
+	return err
+}
@@ -0,0 +1,46 @@
+package processor
+
+// ApplyTolerance counts receipts whose bottles differ from the beer by at
+// most toleranceML as matching and drops their beer vs bottles findings.
+// ApplyAudits keeps them dropped when the audits run again.
+func (r *Report) ApplyTolerance(toleranceML int64) {
+	if toleranceML <= 0 {
+		return
+	}
+
+	r.MismatchCount = 0
+	for i := range r.Receipts {
+		rec := &r.Receipts[i]
+		if !rec.Match && rec.DiffML >= -toleranceML && rec.DiffML <= toleranceML {
+			rec.Match = true
+		}
+		if !rec.Match && rec.Resolution == "" {
+			r.MismatchCount++
+		}
+	}
+	r.Operators = OperatorStats(r.Receipts)
+	r.dropMatchedFindings()
+}
+
+// dropMatchedFindings removes the beer vs bottles findings of receipts the
+// report counts as matching, e.g. within the tolerance.
+func (r *Report) dropMatchedFindings() {
+	matched := make(map[receiptID]bool)
+	for _, rec := range r.Receipts {
+		if rec.Match && rec.Flag == "" {
+			matched[receiptID{register: rec.Register, no: rec.ReceiptNo}] = true
+		}
+	}
+	for i := range r.Audits {
+		if r.Audits[i].Name != AuditBottles {
+			continue
+		}
+		kept := r.Audits[i].Findings[:0]
+		for _, f := range r.Audits[i].Findings {
+			if !matched[receiptID{register: f.Register, no: f.ReceiptNo}] {
+				kept = append(kept, f)
+			}
+		}
+		r.Audits[i].Findings = kept
+	}
+}
@@ -0,0 +1,154 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"bigbrother/internal/i18n"
+)
+
+// VATRule maps a category to the VAT rate the POS is expected to apply.
+// A pattern ending in "*" matches categories by prefix, otherwise the whole
+// category has to match. Matching ignores case.
+type VATRule struct {
+	Pattern   string
+	RateMilli int64
+}
+
+func (r VATRule) matches(category string) bool {
+	if prefix, ok := strings.CutSuffix(r.Pattern, "*"); ok {
+		return hasPrefixFold(category, prefix)
+	}
+	return strings.EqualFold(category, r.Pattern)
+}
+
+// DefaultVATRules returns the rates used by the shop: beer, bottles and
+// other drinks at 21 %, food and snacks at 12 %.
+func DefaultVATRules() []VATRule {
+	rules, err := ParseVATRules(strings.Join([]string{
+		"Pivovar*=21",
+		"Pivo*=21",
+		"PET láhve=21",
+		"Alkohol*=21",
+		"Víno=21",
+		"Nealko nápoje=21",
+		"Cigarettes=21",
+		"Brambůrky/Chips=12",
+		"Čokolady/Pečivo/Zefir=12",
+		"Žvýkačky/bonbony=12",
+		"Ořechy/Tyčinky=12",
+		"Ryby/maso=12",
+		"Konzervy/Těstoviny=12",
+		"Káva/Čaj=12",
+		"Mražené=12",
+	}, ";"))
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// ParseVATRules parses "Category=21;Prefix*=12" into rules, keeping their order.
+func ParseVATRules(raw string) ([]VATRule, error) {
+	var rules []VATRule
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pattern, rate, ok := strings.Cut(part, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid VAT rule: %s", part)
+		}
+		rateMilli, err := parseVATRate(rate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VAT rule %s: %w", part, err)
+		}
+		rules = append(rules, VATRule{Pattern: pattern, RateMilli: rateMilli})
+	}
+	return rules, nil
+}
+
+func parseVATRate(raw string) (int64, error) {
+	raw = strings.TrimSuffix(strings.TrimSpace(raw), "%")
+	rate, err := parseDecimalToMilli(raw)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 100_000 {
+		return 0, fmt.Errorf("rate out of range: %s", raw)
+	}
+	return rate, nil
+}
+
+type vatAudit struct {
+	rules []VATRule
+}
+
+// NewVATAudit flags rows whose DPH rate differs from the rate expected for
+// their category. Categories without a rule are not checked.
+func NewVATAudit(rules []VATRule) Audit {
+	return vatAudit{rules: append([]VATRule(nil), rules...)}
+}
+
+func (vatAudit) Name() string  { return AuditVAT }
+func (vatAudit) Title() string { return "VAT rates" }
+
+func (a vatAudit) Run(receipts []Receipt, p i18n.Printer) []Finding {
+	var findings []Finding
+	for _, rec := range receipts {
+		for _, line := range rec.Lines {
+			if line.VAT == "" {
+				continue
+			}
+			rule, ok := a.ruleFor(line.Category)
+			if !ok {
+				continue
+			}
+			applied, err := parseVATRate(line.VAT)
+			if err != nil {
+				findings = append(findings, Finding{
+					Severity:  SeverityWarning,
+					Subject:   p.Sprintf("Receipt %s", rec.No),
+					ReceiptNo: rec.No,
+					Register:  rec.Register,
+					Message:   p.Sprintf("row %d: unreadable VAT %q", line.Row, line.VAT),
+				})
+				continue
+			}
+			if applied == rule.RateMilli {
+				continue
+			}
+			severity := SeverityWarning
+			if applied < rule.RateMilli {
+				severity = SeverityCritical
+			}
+			findings = append(findings, Finding{
+				Severity:  severity,
+				Subject:   p.Sprintf("Receipt %s", rec.No),
+				ReceiptNo: rec.No,
+				Register:  rec.Register,
+				Message: p.Sprintf("row %d: %s / %s charged %s VAT, expected %s",
+					line.Row, line.Category, line.Product, formatRate(applied), formatRate(rule.RateMilli)),
+			})
+		}
+	}
+	return findings
+}
+
+func (a vatAudit) ruleFor(category string) (VATRule, bool) {
+	for _, rule := range a.rules {
+		if rule.matches(category) {
+			return rule, true
+		}
+	}
+	return VATRule{}, false
+}
+
+func formatRate(milli int64) string {
+	if milli%1000 == 0 {
+		return fmt.Sprintf("%d%%", milli/1000)
+	}
+	return fmt.Sprintf("%.1f%%", float64(milli)/1000.0)
+}
@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"bigbrother/internal/i18n"
+)
+
+func TestVATAudit_WrongRate(t *testing.T) {
+	headers := []string{
+		headerReceipt,
+		headerCategory,
+		headerProduct,
+		headerIssuedAt,
+		headerQuantity,
+		headerVAT,
+	}
+	rows := [][]string{
+		{"R1", "Pivovar Test", "Beer", "2026-02-06 10:00:00", "1", "21.00"},
+		{"R1", "PET láhve", "Láhev 1 l", "2026-02-06 10:00:00", "1", "12.00"},
+		{"R1", "Žvýkačky/bonbony", "Roshen", "2026-02-06 10:00:00", "1", "21,00"},
+		{"R1", "Neznámé", "Něco", "2026-02-06 10:00:00", "1", "0"},
+	}
+	path := writeXLSX(t, headers, rows)
+	report, err := ProcessXLSX(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	findings := NewVATAudit(DefaultVATRules()).Run(report.Parsed, i18n.For(i18n.English))
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got: %+v", findings)
+	}
+	if findings[0].Severity != SeverityCritical || !strings.Contains(findings[0].Message, "charged 12% VAT, expected 21%") {
+		t.Fatalf("unexpected first finding: %+v", findings[0])
+	}
+	if findings[1].Severity != SeverityWarning || !strings.Contains(findings[1].Message, "Roshen") {
+		t.Fatalf("unexpected second finding: %+v", findings[1])
+	}
+}
+
+func TestParseVATRules(t *testing.T) {
+	rules, err := ParseVATRules("Pivovar*=21 ; Snacks=12.5%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 || rules[1].RateMilli != 12500 {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+	if !rules[0].matches("pivovar Raven") || rules[1].matches("Snacks extra") {
+		t.Fatalf("unexpected matching: %+v", rules)
+	}
+	if _, err := ParseVATRules("Broken"); err == nil {
+		t.Fatal("expected error for rule without rate")
+	}
+}
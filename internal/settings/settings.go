@@ -0,0 +1,213 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Snark levels: no remarks, remarks on reports with mismatches, remarks on
+// every report.
+const (
+	SnarkOff      = "off"
+	SnarkMismatch = "mismatch"
+	SnarkAlways   = "always"
+)
+
+// SnarkLevels lists the valid snark levels in the order they are offered.
+var SnarkLevels = []string{SnarkOff, SnarkMismatch, SnarkAlways}
+
+// Settings are the values in effect for a chat.
+type Settings struct {
+	ToleranceML int64
+	Snark       string
+	Tone        string
+	Timezone    string
+	Audits      []string
+	Profile     string
+	// Language is the language of replies and reports. Empty follows the
+	// language of the Telegram client of whoever wrote to the bot.
+	Language string
+	// Phrases are the chat's own phrases by kind, added with /snark add.
+	Phrases map[string][]string
+}
+
+// Location returns the chat's time zone. An empty or unknown zone falls back
+// to the zone of the host.
+func (s Settings) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Overrides are the settings a chat changed. Nil fields use the defaults,
+// so a chat follows later changes to the global config for everything it
+// did not set itself.
+type Overrides struct {
+	ToleranceML *int64    `json:"tolerance_ml,omitempty"`
+	Snark       *string   `json:"snark,omitempty"`
+	Tone        *string   `json:"tone,omitempty"`
+	Timezone    *string   `json:"timezone,omitempty"`
+	Audits      *[]string `json:"audits,omitempty"`
+	Profile     *string   `json:"profile,omitempty"`
+	Language    *string   `json:"language,omitempty"`
+	// Phrases add to the built-in phrases rather than replace a default.
+	Phrases map[string][]string `json:"phrases,omitempty"`
+}
+
+// Apply returns defaults with the overrides applied.
+func (o Overrides) Apply(defaults Settings) Settings {
+	s := defaults
+	s.Audits = append([]string(nil), defaults.Audits...)
+	if o.ToleranceML != nil {
+		s.ToleranceML = *o.ToleranceML
+	}
+	if o.Snark != nil {
+		s.Snark = *o.Snark
+	}
+	if o.Tone != nil {
+		s.Tone = *o.Tone
+	}
+	if o.Timezone != nil {
+		s.Timezone = *o.Timezone
+	}
+	if o.Audits != nil {
+		s.Audits = append([]string{}, (*o.Audits)...)
+	}
+	if o.Profile != nil {
+		s.Profile = *o.Profile
+	}
+	if o.Language != nil {
+		s.Language = *o.Language
+	}
+	s.Phrases = make(map[string][]string, len(o.Phrases))
+	for kind, phrases := range o.Phrases {
+		s.Phrases[kind] = append([]string(nil), phrases...)
+	}
+	return s
+}
+
+// Store keeps the overrides of every chat in its own JSON file under
+// DATA_DIR/settings.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	defaults Settings
+}
+
+// NewStore creates a store under dataDir falling back to defaults.
+func NewStore(dataDir string, defaults Settings) *Store {
+	return &Store{dir: filepath.Join(dataDir, "settings"), defaults: defaults}
+}
+
+// Defaults returns the global settings.
+func (s *Store) Defaults() Settings {
+	return Overrides{}.Apply(s.defaults)
+}
+
+// Get returns the settings in effect for a chat.
+func (s *Store) Get(chatID int64) (Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.load(chatID)
+	if err != nil {
+		return s.Defaults(), err
+	}
+	return o.Apply(s.defaults), nil
+}
+
+// Overrides returns what a chat changed.
+func (s *Store) Overrides(chatID int64) (Overrides, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(chatID)
+}
+
+// Update applies fn to the overrides of a chat, saves them and returns the
+// resulting settings. fn gets the effective settings to build on.
+func (s *Store) Update(chatID int64, fn func(o *Overrides, current Settings) error) (Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.load(chatID)
+	if err != nil {
+		return Settings{}, err
+	}
+	if err := fn(&o, o.Apply(s.defaults)); err != nil {
+		return Settings{}, err
+	}
+	if err := s.save(chatID, o); err != nil {
+		return Settings{}, err
+	}
+	return o.Apply(s.defaults), nil
+}
+
+// Reset drops every override of a chat.
+func (s *Store) Reset(chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(chatID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove settings: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) path(chatID int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(chatID, 10)+".json")
+}
+
+func (s *Store) load(chatID int64) (Overrides, error) {
+	var o Overrides
+	data, err := os.ReadFile(s.path(chatID))
+	if errors.Is(err, os.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return o, fmt.Errorf("read settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &o); err != nil {
+		return Overrides{}, fmt.Errorf("decode settings: %w", err)
+	}
+	return o, nil
+}
+
+func (s *Store) save(chatID int64, o Overrides) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".settings-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write settings: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close settings: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(chatID)); err != nil {
+		return fmt.Errorf("rename settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package settings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_FallsBackToDefaults(t *testing.T) {
+	dir := t.TempDir()
+	defaults := Settings{ToleranceML: 0, Snark: SnarkMismatch, Audits: []string{"bottles", "vat"}, Profile: "default"}
+	store := NewStore(dir, defaults)
+
+	got, err := store.Get(1)
+	if err != nil || got.Snark != SnarkMismatch || len(got.Audits) != 2 {
+		t.Fatalf("expected defaults, got %+v (%v)", got, err)
+	}
+
+	_, err = store.Update(1, func(o *Overrides, current Settings) error {
+		tolerance := int64(50)
+		none := []string{}
+		o.ToleranceML = &tolerance
+		o.Audits = &none
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// A restart with new global defaults keeps the overrides and picks up
+	// the rest.
+	defaults.Snark = SnarkOff
+	store = NewStore(dir, defaults)
+	got, err = store.Get(1)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.ToleranceML != 50 || got.Audits == nil || len(got.Audits) != 0 || got.Snark != SnarkOff {
+		t.Fatalf("unexpected settings: %+v", got)
+	}
+	if other, _ := store.Get(2); other.ToleranceML != 0 || len(other.Audits) != 2 {
+		t.Fatalf("expected other chats to keep defaults, got %+v", other)
+	}
+
+	if err := store.Reset(1); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if got, _ := store.Get(1); got.ToleranceML != 0 || len(got.Audits) != 2 {
+		t.Fatalf("expected defaults after reset, got %+v", got)
+	}
+}
+
+func TestSettings_Location(t *testing.T) {
+	if loc := (Settings{Timezone: "Europe/Prague"}).Location(); loc.String() != "Europe/Prague" {
+		t.Fatalf("unexpected location: %s", loc)
+	}
+	if loc := (Settings{Timezone: "Mars/Olympus"}).Location(); loc != time.Local {
+		t.Fatalf("expected fallback to local time, got %s", loc)
+	}
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const metaSuffix = ".meta.json"
+
+// ArchiveMeta is stored with every archived upload.
+type ArchiveMeta struct {
+	ChatID       int64
+	UploaderID   int64
+	Uploader     string
+	OriginalName string
+	SHA256       string
+	Size         int64
+	ReportID     int
+	ArchivedAt   time.Time
+}
+
+func (m ArchiveMeta) toMap() map[string]string {
+	return map[string]string{
+		"chat-id":       strconv.FormatInt(m.ChatID, 10),
+		"uploader-id":   strconv.FormatInt(m.UploaderID, 10),
+		"uploader":      m.Uploader,
+		"original-name": m.OriginalName,
+		"sha256":        m.SHA256,
+		"size":          strconv.FormatInt(m.Size, 10),
+		"report-id":     strconv.Itoa(m.ReportID),
+		"archived-at":   m.ArchivedAt.Format(time.RFC3339),
+	}
+}
+
+func archiveMetaFromMap(meta map[string]string) ArchiveMeta {
+	m := ArchiveMeta{
+		Uploader:     meta["uploader"],
+		OriginalName: meta["original-name"],
+		SHA256:       meta["sha256"],
+	}
+	m.ChatID, _ = strconv.ParseInt(meta["chat-id"], 10, 64)
+	m.UploaderID, _ = strconv.ParseInt(meta["uploader-id"], 10, 64)
+	m.Size, _ = strconv.ParseInt(meta["size"], 10, 64)
+	m.ReportID, _ = strconv.Atoi(meta["report-id"])
+	m.ArchivedAt, _ = time.Parse(time.RFC3339, meta["archived-at"])
+	return m
+}
+
+// Archive keeps processed uploads under archive/<chat>/<date>/ in a Store.
+type Archive struct {
+	store Store
+}
+
+func NewArchive(store Store) *Archive {
+	return &Archive{store: store}
+}
+
+// Add uploads the file at path to the archive with its metadata and removes
+// the local file. It returns the archive key.
+func (a *Archive) Add(ctx context.Context, path string, meta ArchiveMeta) (string, error) {
+	if meta.ArchivedAt.IsZero() {
+		meta.ArchivedAt = time.Now()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open upload: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat upload: %w", err)
+	}
+	meta.Size = info.Size()
+
+	key := strings.Join([]string{
+		"archive",
+		strconv.FormatInt(meta.ChatID, 10),
+		meta.ArchivedAt.Format("2006-01-02"),
+		filepath.Base(path),
+	}, "/")
+	if err := a.store.Put(ctx, key, f, meta.toMap()); err != nil {
+		return "", fmt.Errorf("archive upload: %w", err)
+	}
+	_ = f.Close()
+	_ = os.Remove(path)
+	return key, nil
+}
+
+// Meta returns the metadata of an archived upload.
+func (a *Archive) Meta(ctx context.Context, key string) (ArchiveMeta, error) {
+	body, obj, err := a.store.Get(ctx, key)
+	if err != nil {
+		return ArchiveMeta{}, err
+	}
+	_ = body.Close()
+	return archiveMetaFromMap(obj.Meta), nil
+}
+
+// Cleanup removes uploads older than maxAge, then the oldest uploads until
+// the archive is at most maxBytes. A zero limit is not enforced. It returns
+// the number of removed uploads.
+func (a *Archive) Cleanup(ctx context.Context, now time.Time, maxAge time.Duration, maxBytes int64) (int, error) {
+	objects, err := a.store.List(ctx, "archive/")
+	if err != nil {
+		return 0, fmt.Errorf("list archive: %w", err)
+	}
+
+	for i := range objects {
+		objects[i].Modified = archivedAt(objects[i])
+	}
+	sort.SliceStable(objects, func(i, j int) bool { return objects[i].Modified.Before(objects[j].Modified) })
+	var total int64
+	for _, obj := range objects {
+		total += obj.Size
+	}
+
+	removed := 0
+	for _, obj := range objects {
+		expired := maxAge > 0 && now.Sub(obj.Modified) > maxAge
+		oversize := maxBytes > 0 && total > maxBytes
+		if !expired && !oversize {
+			break
+		}
+		if err := a.store.Delete(ctx, obj.Key); err != nil {
+			return removed, err
+		}
+		total -= obj.Size
+		removed++
+	}
+	return removed, nil
+}
+
+// archivedAt returns when obj was archived. Rewriting an object, as key
+// rotation does, moves its modification time, so that time only counts up
+// to the end of the day named in the archive key.
+func archivedAt(obj Object) time.Time {
+	parts := strings.Split(obj.Key, "/")
+	if len(parts) < 4 {
+		return obj.Modified
+	}
+	day, err := time.ParseInLocation("2006-01-02", parts[2], time.Local)
+	if err != nil {
+		return obj.Modified
+	}
+	if end := day.AddDate(0, 0, 1); obj.Modified.After(end) {
+		return end
+	}
+	return obj.Modified
+}
+
+// RunJanitor calls Cleanup every interval until ctx is done.
+func (a *Archive) RunJanitor(ctx context.Context, interval, maxAge time.Duration, maxBytes int64) {
+	if maxAge <= 0 && maxBytes <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if n, err := a.Cleanup(ctx, time.Now(), maxAge, maxBytes); err != nil {
+			log.Printf("archive cleanup: %v", err)
+		} else if n > 0 {
+			log.Printf("archive cleanup: removed %d uploads", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
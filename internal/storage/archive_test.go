@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive_AddWritesMetadata(t *testing.T) {
+	dataDir := t.TempDir()
+	src := filepath.Join(dataDir, "upload.csv")
+	if err := os.WriteFile(src, []byte("a;b\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ctx := context.Background()
+	archive := NewArchive(NewLocalStore(dataDir))
+	at := time.Date(2026, 2, 6, 22, 0, 0, 0, time.UTC)
+	key, err := archive.Add(ctx, src, ArchiveMeta{ChatID: 7, Uploader: "@jana", OriginalName: "sales.csv", SHA256: "abc", ReportID: 3, ArchivedAt: at})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if key != "archive/7/2026-02-06/upload.csv" {
+		t.Fatalf("unexpected archive key: %s", key)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("expected upload to be removed, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "archive", "7", "2026-02-06", "upload.csv"+metaSuffix)); err != nil {
+		t.Fatalf("expected metadata sidecar: %v", err)
+	}
+
+	meta, err := archive.Meta(ctx, key)
+	if err != nil {
+		t.Fatalf("meta: %v", err)
+	}
+	if meta.ReportID != 3 || meta.Size != 4 || meta.Uploader != "@jana" || meta.OriginalName != "sales.csv" || !meta.ArchivedAt.Equal(at) {
+		t.Fatalf("unexpected metadata: %+v", meta)
+	}
+}
+
+func TestArchive_CleanupByAgeAndSize(t *testing.T) {
+	dataDir := t.TempDir()
+	ctx := context.Background()
+	archive := NewArchive(NewLocalStore(dataDir))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for i, age := range []time.Duration{40 * 24 * time.Hour, 3 * 24 * time.Hour, 2 * 24 * time.Hour, time.Hour} {
+		src := filepath.Join(dataDir, "f"+string(rune('a'+i)))
+		if err := os.WriteFile(src, make([]byte, 100), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		key, err := archive.Add(ctx, src, ArchiveMeta{ChatID: 1, ArchivedAt: now.Add(-age)})
+		if err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		path := filepath.Join(dataDir, filepath.FromSlash(key))
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	removed, err := archive.Cleanup(ctx, now, 30*24*time.Hour, 250)
+	if err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if removed != 2 {
+		t.Fatalf("expected 2 removed uploads, got %d", removed)
+	}
+	for i, path := range paths {
+		_, err := os.Stat(path)
+		if kept := i >= 2; kept != (err == nil) {
+			t.Fatalf("upload %d: expected kept=%v, got err %v", i, kept, err)
+		}
+		if _, err := os.Stat(path + metaSuffix); (err == nil) != (i >= 2) {
+			t.Fatalf("sidecar %d not handled with its upload: %v", i, err)
+		}
+	}
+}
+
+func TestArchive_CleanupIgnoresRewrites(t *testing.T) {
+	dataDir := t.TempDir()
+	ctx := context.Background()
+	archive := NewArchive(NewLocalStore(dataDir))
+	now := time.Now()
+
+	src := filepath.Join(dataDir, "upload.csv")
+	if err := os.WriteFile(src, []byte("a;b\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// Archived long ago, then rewritten by a key rotation just now.
+	key, err := archive.Add(ctx, src, ArchiveMeta{ChatID: 1, ArchivedAt: now.AddDate(0, 0, -40)})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	removed, err := archive.Cleanup(ctx, now, 30*24*time.Hour, 0)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected the old upload removed, got %d (%v)", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, filepath.FromSlash(key))); !os.IsNotExist(err) {
+		t.Fatalf("expected upload to be removed, got: %v", err)
+	}
+}
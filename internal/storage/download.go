@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Downloader fetches files over HTTP. Network errors and 5xx responses are
+// retried with jittered exponential backoff, and a download cut off midway
+// resumes with a Range request instead of starting over.
+type Downloader struct {
+	Client      *http.Client
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// NewDownloader returns a downloader with connection timeouts suited for
+// Telegram file downloads. The overall deadline comes from the context.
+func NewDownloader() *Downloader {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          10,
+	}
+	return &Downloader{
+		Client:      &http.Client{Transport: transport},
+		MaxAttempts: 5,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// errPermanent marks failures that retrying will not fix.
+type errPermanent struct{ err error }
+
+func (e errPermanent) Error() string { return e.err.Error() }
+func (e errPermanent) Unwrap() error { return e.err }
+
+// Download writes the body at url to f, which must be empty. maxBytes > 0
+// limits the size. It returns the number of bytes written.
+func (d *Downloader) Download(ctx context.Context, url string, f *os.File, maxBytes int64) (int64, error) {
+	attempts := max(1, d.MaxAttempts)
+	var written int64
+	var lastErr error
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if err := d.wait(ctx, attempt-1); err != nil {
+				return written, err
+			}
+		}
+
+		n, done, err := d.fetch(ctx, url, f, written, maxBytes)
+		written = n
+		if done {
+			return written, nil
+		}
+		var permanent errPermanent
+		if errors.As(err, &permanent) || ctx.Err() != nil {
+			return written, err
+		}
+		lastErr = err
+	}
+	return written, fmt.Errorf("download failed after %d attempts: %w", attempts, lastErr)
+}
+
+// fetch makes one request, resuming at offset when it is not zero. It
+// returns the bytes now in f and whether the download is complete.
+func (d *Downloader) fetch(ctx context.Context, url string, f *os.File, offset, maxBytes int64) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return offset, false, errPermanent{fmt.Errorf("create request: %w", err)}
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return offset, false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && rangeStart(resp) == offset:
+		// Resume where the last attempt stopped.
+	case resp.StatusCode == http.StatusPartialContent:
+		// A part we did not ask for cannot be appended; start over without
+		// a range on the next attempt.
+		if err := restart(f); err != nil {
+			return 0, false, errPermanent{err}
+		}
+		return 0, false, fmt.Errorf("download failed: unexpected range %q", resp.Header.Get("Content-Range"))
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		// Full body, either first attempt or the server ignored the range.
+		if err := restart(f); err != nil {
+			return 0, false, errPermanent{err}
+		}
+		offset = 0
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return offset, false, fmt.Errorf("download failed: status %s", resp.Status)
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The saved part is not usable, start over on the next attempt.
+		if err := restart(f); err != nil {
+			return 0, false, errPermanent{err}
+		}
+		return 0, false, fmt.Errorf("download failed: status %s", resp.Status)
+	default:
+		return offset, false, errPermanent{fmt.Errorf("download failed: status %s", resp.Status)}
+	}
+
+	if maxBytes > 0 && resp.ContentLength > 0 && offset+resp.ContentLength > maxBytes {
+		return offset, false, errPermanent{fmt.Errorf("file too large: %d bytes (max %d)", offset+resp.ContentLength, maxBytes)}
+	}
+
+	reader := io.Reader(resp.Body)
+	if maxBytes > 0 {
+		reader = io.LimitReader(resp.Body, maxBytes-offset+1)
+	}
+	n, err := io.Copy(f, reader)
+	offset += n
+	if maxBytes > 0 && offset > maxBytes {
+		return offset, false, errPermanent{fmt.Errorf("file too large: wrote %d bytes (max %d)", offset, maxBytes)}
+	}
+	if err != nil {
+		return offset, false, fmt.Errorf("read body: %w", err)
+	}
+	return offset, true, nil
+}
+
+func (d *Downloader) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return http.DefaultClient
+}
+
+// wait sleeps before retry number n: a random duration between half and all
+// of BaseDelay*2^(n-1), capped at MaxDelay.
+func (d *Downloader) wait(ctx context.Context, n int) error {
+	delay := d.BaseDelay << (n - 1)
+	if d.MaxDelay > 0 && (delay > d.MaxDelay || delay <= 0) {
+		delay = d.MaxDelay
+	}
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rangeStart returns the first byte of a "Content-Range: bytes a-b/c" header,
+// or -1 when it is missing or malformed.
+func rangeStart(resp *http.Response) int64 {
+	spec, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func restart(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("truncate file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seek file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testDownloader() *Downloader {
+	return &Downloader{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func download(t *testing.T, d *Downloader, url string, maxBytes int64) (string, error) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "download"))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := d.Download(context.Background(), url, f, maxBytes); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(data), nil
+}
+
+// dropAfter writes the first n bytes of the response and then kills the connection.
+func dropAfter(w http.ResponseWriter, payload string, n int) {
+	w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+	_, _ = w.Write([]byte(payload[:n]))
+	w.(http.Flusher).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+func TestDownloader_ResumesDroppedConnection(t *testing.T) {
+	payload := strings.Repeat("0123456789", 100)
+	var mu sync.Mutex
+	var ranges []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		attempt := len(ranges)
+		mu.Unlock()
+
+		if attempt == 1 {
+			dropAfter(w, payload, 300)
+		}
+		start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "-"))
+		if err != nil {
+			t.Errorf("expected a range request, got %q", r.Header.Get("Range"))
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(payload)-1, len(payload)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte(payload[start:]))
+	}))
+	defer srv.Close()
+
+	got, err := download(t, testDownloader(), srv.URL, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != payload {
+		t.Fatalf("corrupted download: %d bytes", len(got))
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=300-" {
+		t.Fatalf("unexpected range requests: %q", ranges)
+	}
+}
+
+func TestDownloader_RestartsWhenRangeIgnored(t *testing.T) {
+	payload := strings.Repeat("abc", 100)
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			dropAfter(w, payload, 100)
+		}
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer srv.Close()
+
+	got, err := download(t, testDownloader(), srv.URL, 0)
+	if err != nil || got != payload {
+		t.Fatalf("expected full payload once, got %d bytes (%v)", len(got), err)
+	}
+}
+
+func TestDownloader_RestartsOnWrongContentRange(t *testing.T) {
+	payload := strings.Repeat("0123456789", 100)
+	var mu sync.Mutex
+	var ranges []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		attempt := len(ranges)
+		mu.Unlock()
+
+		switch attempt {
+		case 1:
+			dropAfter(w, payload, 300)
+		case 2:
+			// Answers the resume with a part starting elsewhere.
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 100-%d/%d", len(payload)-1, len(payload)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(payload[100:]))
+		default:
+			_, _ = w.Write([]byte(payload))
+		}
+	}))
+	defer srv.Close()
+
+	got, err := download(t, testDownloader(), srv.URL, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != payload {
+		t.Fatalf("corrupted download: %d bytes", len(got))
+	}
+	if len(ranges) != 3 || ranges[1] != "bytes=300-" || ranges[2] != "" {
+		t.Fatalf("expected a retry without a range, got: %q", ranges)
+	}
+}
+
+func TestDownloader_RetriesServerErrors(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	got, err := download(t, testDownloader(), srv.URL, 0)
+	if err != nil || got != "ok" || attempts != 3 {
+		t.Fatalf("expected success on attempt 3, got %q after %d attempts (%v)", got, attempts, err)
+	}
+}
+
+func TestDownloader_GivesUp(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	if _, err := download(t, testDownloader(), srv.URL+"/missing", 0); err == nil || attempts != 1 {
+		t.Fatalf("expected 404 to fail without retries, got %d attempts (%v)", attempts, err)
+	}
+
+	attempts = 0
+	_, err := download(t, testDownloader(), srv.URL, 0)
+	if err == nil || !strings.Contains(err.Error(), "after 4 attempts") || attempts != 4 {
+		t.Fatalf("expected failure after 4 attempts, got %d (%v)", attempts, err)
+	}
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// encryptedMagic starts every object written by EncryptedStore. It is
+// followed by the key ID length, the key ID, the nonce and the sealed data.
+const encryptedMagic = "BBE1"
+
+// Keyring holds AES-256 keys by ID. The current key encrypts new objects;
+// the others are kept to read objects written before a rotation.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// ParseKeyring parses keys in the form "id:base64key", separated by commas
+// or newlines. The first key is the current one. Blank lines and lines
+// starting with # are ignored.
+func ParseKeyring(raw string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == '\n' })
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || strings.HasPrefix(field, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(field, ":")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("invalid key entry %q, expected id:base64key", id)
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("duplicate key ID %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes, base64-encoded", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keys[id] = aead
+		if k.current == "" {
+			k.current = id
+		}
+	}
+	if k.current == "" {
+		return nil, errors.New("no encryption keys")
+	}
+	return k, nil
+}
+
+// Current returns the ID of the key used for new objects.
+func (k *Keyring) Current() string {
+	return k.current
+}
+
+// seal encrypts data with the current key. The object key is authenticated
+// too, so a ciphertext cannot be moved to another object unnoticed.
+func (k *Keyring) seal(key string, data []byte) ([]byte, error) {
+	aead := k.keys[k.current]
+	header := append([]byte(encryptedMagic), byte(len(k.current)))
+	header = append(header, k.current...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	aad := append(append([]byte(nil), header...), key...)
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, data, aad), nil
+}
+
+// open decrypts data written by seal and returns the ID of the key used.
+// Unless allowPlain is false, data without the header is returned unchanged, so
+// objects stored before encryption was enabled stay readable.
+func (k *Keyring) open(key string, data []byte, allowPlain bool) ([]byte, string, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedMagic)) {
+		if !allowPlain {
+			return nil, "", fmt.Errorf("decrypt %s: %w", key, ErrNotEncrypted)
+		}
+		return data, "", nil
+	}
+	rest := data[len(encryptedMagic):]
+	if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+		return nil, "", fmt.Errorf("decrypt %s: truncated header", key)
+	}
+	id := string(rest[1 : 1+rest[0]])
+	header := data[:len(encryptedMagic)+1+len(id)]
+	rest = rest[1+len(id):]
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return nil, id, fmt.Errorf("decrypt %s: unknown key ID %q", key, id)
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, id, fmt.Errorf("decrypt %s: truncated nonce", key)
+	}
+	nonce, sealed := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, append(append([]byte(nil), header...), key...))
+	if err != nil {
+		return nil, id, fmt.Errorf("decrypt %s: %w", key, err)
+	}
+	return plain, id, nil
+}
+
+// ErrNotEncrypted is returned by an EncryptedStore that requires encryption
+// for an object stored in plain text.
+var ErrNotEncrypted = errors.New("object is not encrypted")
+
+// EncryptedStore encrypts object contents with AES-GCM before passing them
+// to the underlying store and decrypts them on Get. Metadata is stored as is,
+// and List reports stored sizes, which include the encryption overhead.
+type EncryptedStore struct {
+	Store
+	keys *Keyring
+
+	// RequireEncrypted refuses objects stored in plain text instead of
+	// returning them as is. Rotate still reads them, to encrypt them. It
+	// says nothing about metadata, which is never encrypted.
+	RequireEncrypted bool
+}
+
+// NewEncryptedStore wraps store so that everything written through it is
+// encrypted with the current key of keys.
+func NewEncryptedStore(store Store, keys *Keyring) *EncryptedStore {
+	return &EncryptedStore{Store: store, keys: keys}
+}
+
+func (s *EncryptedStore) Put(ctx context.Context, key string, r io.Reader, meta map[string]string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", key, err)
+	}
+	sealed, err := s.keys.seal(key, data)
+	if err != nil {
+		return err
+	}
+	return s.Store.Put(ctx, key, bytes.NewReader(sealed), meta)
+}
+
+func (s *EncryptedStore) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	data, obj, _, err := s.get(ctx, key, !s.RequireEncrypted)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	obj.Size = int64(len(data))
+	return io.NopCloser(bytes.NewReader(data)), obj, nil
+}
+
+func (s *EncryptedStore) get(ctx context.Context, key string, allowPlain bool) ([]byte, Object, string, error) {
+	body, obj, err := s.Store.Get(ctx, key)
+	if err != nil {
+		return nil, Object{}, "", err
+	}
+	data, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil {
+		return nil, Object{}, "", fmt.Errorf("read %s: %w", key, err)
+	}
+	opened, id, err := s.keys.open(key, data, allowPlain)
+	if err != nil {
+		return nil, Object{}, "", err
+	}
+	return opened, obj, id, nil
+}
+
+// Rotate re-encrypts every object under prefix that is not yet encrypted
+// with the current key, including objects stored before encryption was
+// enabled. Once it has run, retired keys can be removed from the keyring.
+// It returns the number of objects rewritten.
+func (s *EncryptedStore) Rotate(ctx context.Context, prefix string) (int, error) {
+	objects, err := s.Store.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	rewritten := 0
+	for _, o := range objects {
+		if err := ctx.Err(); err != nil {
+			return rewritten, err
+		}
+		data, obj, id, err := s.get(ctx, o.Key, true)
+		if errors.Is(err, ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return rewritten, err
+		}
+		if id == s.keys.Current() {
+			continue
+		}
+		if err := s.Put(ctx, o.Key, bytes.NewReader(data), obj.Meta); err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+	return rewritten, nil
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func mustKeyring(t *testing.T, raw string) *Keyring {
+	t.Helper()
+	k, err := ParseKeyring(raw)
+	if err != nil {
+		t.Fatalf("parse keyring: %v", err)
+	}
+	return k
+}
+
+func readObject(t *testing.T, store Store, key string) (string, error) {
+	t.Helper()
+	body, _, err := store.Get(context.Background(), key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(data), nil
+}
+
+func TestEncryptedStore_RoundTrip(t *testing.T) {
+	// Header with a two-byte key ID, nonce and GCM tag.
+	overhead := int64(len(encryptedMagic) + 1 + 2 + 12 + 16)
+	testStoreRoundTrip(t, NewEncryptedStore(NewLocalStore(t.TempDir()), mustKeyring(t, "k1:"+testKey(1))), overhead)
+}
+
+func TestEncryptedStore_EncryptsAtRest(t *testing.T) {
+	root := t.TempDir()
+	store := NewEncryptedStore(NewLocalStore(root), mustKeyring(t, "k1:"+testKey(1)))
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "reports/1/000001.json", strings.NewReader(`{"receipt":"R1"}`), nil); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(root, "reports", "1", "000001.json"))
+	if err != nil {
+		t.Fatalf("read raw: %v", err)
+	}
+	if bytes.Contains(raw, []byte("R1")) || !bytes.HasPrefix(raw, []byte(encryptedMagic+"\x02k1")) {
+		t.Fatalf("expected ciphertext with key ID header, got %q", raw)
+	}
+
+	// A ciphertext copied over another object must not decrypt.
+	if err := os.WriteFile(filepath.Join(root, "reports", "1", "000002.json"), raw, 0o644); err != nil {
+		t.Fatalf("write copy: %v", err)
+	}
+	if _, err := readObject(t, store, "reports/1/000002.json"); err == nil {
+		t.Fatal("expected moved ciphertext to fail authentication")
+	}
+
+	raw[len(raw)-1] ^= 1
+	if err := os.WriteFile(filepath.Join(root, "reports", "1", "000001.json"), raw, 0o644); err != nil {
+		t.Fatalf("write tampered: %v", err)
+	}
+	if _, err := readObject(t, store, "reports/1/000001.json"); err == nil {
+		t.Fatal("expected tampered ciphertext to fail authentication")
+	}
+}
+
+func TestEncryptedStore_Rotate(t *testing.T) {
+	local := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	if err := local.Put(ctx, "reports/1/000001.json", strings.NewReader("plain"), nil); err != nil {
+		t.Fatalf("put plain: %v", err)
+	}
+	old := NewEncryptedStore(local, mustKeyring(t, "old:"+testKey(1)))
+	if err := old.Put(ctx, "reports/1/000002.json", strings.NewReader("secret"), map[string]string{"sha256": "abc"}); err != nil {
+		t.Fatalf("put old: %v", err)
+	}
+
+	rotated := NewEncryptedStore(local, mustKeyring(t, "new:"+testKey(2)+"\nold:"+testKey(1)))
+	for key, want := range map[string]string{"reports/1/000001.json": "plain", "reports/1/000002.json": "secret"} {
+		if got, err := readObject(t, rotated, key); err != nil || got != want {
+			t.Fatalf("%s: expected %q, got %q (%v)", key, want, got, err)
+		}
+	}
+
+	n, err := rotated.Rotate(ctx, "reports/")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 objects rewritten, got %d (%v)", n, err)
+	}
+	if n, err := rotated.Rotate(ctx, "reports/"); err != nil || n != 0 {
+		t.Fatalf("expected nothing left to rotate, got %d (%v)", n, err)
+	}
+
+	newOnly := NewEncryptedStore(local, mustKeyring(t, "new:"+testKey(2)))
+	if got, err := readObject(t, newOnly, "reports/1/000002.json"); err != nil || got != "secret" {
+		t.Fatalf("expected rotated object readable with the new key only, got %q (%v)", got, err)
+	}
+	if _, obj, err := newOnly.Get(ctx, "reports/1/000002.json"); err != nil || obj.Meta["sha256"] != "abc" {
+		t.Fatalf("expected metadata kept across rotation, got %+v (%v)", obj, err)
+	}
+	if _, err := readObject(t, old, "reports/1/000001.json"); err == nil || !strings.Contains(err.Error(), `unknown key ID "new"`) {
+		t.Fatalf("expected unknown key error, got: %v", err)
+	}
+}
+
+func TestEncryptedStore_RequireEncrypted(t *testing.T) {
+	local := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	if err := local.Put(ctx, "reports/1/000001.json", strings.NewReader("plain"), nil); err != nil {
+		t.Fatalf("put plain: %v", err)
+	}
+
+	store := NewEncryptedStore(local, mustKeyring(t, "k1:"+testKey(1)))
+	store.RequireEncrypted = true
+	if _, err := readObject(t, store, "reports/1/000001.json"); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("expected plain text to be refused, got: %v", err)
+	}
+
+	if n, err := store.Rotate(ctx, "reports/"); err != nil || n != 1 {
+		t.Fatalf("expected rotate to encrypt the plain object, got %d (%v)", n, err)
+	}
+	if got, err := readObject(t, store, "reports/1/000001.json"); err != nil || got != "plain" {
+		t.Fatalf("expected the rotated object to be readable, got %q (%v)", got, err)
+	}
+}
+
+func TestParseKeyring_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "# only a comment", "k1", "k1:" + testKey(1)[:20], "k1:" + testKey(1) + ",k1:" + testKey(2)} {
+		if _, err := ParseKeyring(raw); err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,19 +20,31 @@ type SaveInput struct {
 	ChatID       int64
 	OriginalName string
 	MaxBytes     int64
+	// Downloader fetches FileURL; nil means NewDownloader().
+	Downloader *Downloader
 }
 
-func SaveIncomingFile(ctx context.Context, in SaveInput) (string, error) {
+// SavedFile is a downloaded upload with the size and SHA-256 of its content.
+type SavedFile struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// maxNameAttempts bounds the search for a free file name.
+const maxNameAttempts = 100
+
+func SaveIncomingFile(ctx context.Context, in SaveInput) (SavedFile, error) {
 	if in.FileURL == "" {
-		return "", fmt.Errorf("file URL is empty")
+		return SavedFile{}, fmt.Errorf("file URL is empty")
 	}
 	if in.DataDir == "" {
-		return "", fmt.Errorf("data dir is empty")
+		return SavedFile{}, fmt.Errorf("data dir is empty")
 	}
 
 	dir := filepath.Join(in.DataDir, "incoming")
 	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", fmt.Errorf("mkdir: %w", err)
+		return SavedFile{}, fmt.Errorf("mkdir: %w", err)
 	}
 
 	safeName := sanitizeFilename(in.OriginalName)
@@ -38,51 +53,94 @@ func SaveIncomingFile(ctx context.Context, in SaveInput) (string, error) {
 		safeName = safeName + ".xlsx"
 	}
 
-	timestamp := time.Now().UTC().Format("20060102T150405Z")
-	filename := fmt.Sprintf("%s_%d_%s", timestamp, in.ChatID, safeName)
-	dstPath := filepath.Join(dir, filename)
+	downloader := in.Downloader
+	if downloader == nil {
+		downloader = NewDownloader()
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, in.FileURL, nil)
+	// Download into a temp file so a failed or partial download never shows
+	// up under a real name.
+	tmp, err := os.CreateTemp(dir, ".download-*")
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return SavedFile{}, fmt.Errorf("create temp file: %w", err)
 	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
 
-	resp, err := http.DefaultClient.Do(req)
+	written, err := downloader.Download(ctx, in.FileURL, tmp, in.MaxBytes)
 	if err != nil {
-		return "", fmt.Errorf("download file: %w", err)
+		_ = tmp.Close()
+		return SavedFile{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("download failed: status %s", resp.Status)
+	// Hash what actually landed on disk; a resumed download arrives in parts.
+	hash := sha256.New()
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		_ = tmp.Close()
+		return SavedFile{}, fmt.Errorf("seek file: %w", err)
 	}
-
-	if in.MaxBytes > 0 && resp.ContentLength > 0 && resp.ContentLength > in.MaxBytes {
-		return "", fmt.Errorf("file too large: %d bytes (max %d)", resp.ContentLength, in.MaxBytes)
+	if _, err := io.Copy(hash, tmp); err != nil {
+		_ = tmp.Close()
+		return SavedFile{}, fmt.Errorf("hash file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return SavedFile{}, fmt.Errorf("sync file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return SavedFile{}, fmt.Errorf("close file: %w", err)
 	}
 
-	out, err := os.Create(dstPath)
+	timestamp := time.Now().UTC().Format("20060102T150405Z")
+	dstPath, err := renameUnique(tmp.Name(), dir, fmt.Sprintf("%s_%d", timestamp, in.ChatID), safeName)
 	if err != nil {
-		return "", fmt.Errorf("create file: %w", err)
+		return SavedFile{}, err
 	}
-	defer out.Close()
 
-	reader := io.Reader(resp.Body)
-	if in.MaxBytes > 0 {
-		reader = io.LimitReader(resp.Body, in.MaxBytes+1)
+	return SavedFile{Path: dstPath, Size: written, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// renameUnique gives the file at src the name "<prefix>_<name>" in dir,
+// adding a counter when that name is taken. The name is reserved with an
+// exclusive create before src is renamed onto it, so nothing else is ever
+// overwritten; this also works where hard links are not supported.
+func renameUnique(src, dir, prefix, name string) (string, error) {
+	for i := 1; i <= maxNameAttempts; i++ {
+		filename := prefix + "_" + name
+		if i > 1 {
+			filename = fmt.Sprintf("%s_%d_%s", prefix, i, name)
+		}
+		dst := filepath.Join(dir, filename)
+		f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("name file: %w", err)
+		}
+		_ = f.Close()
+		if err := os.Rename(src, dst); err != nil {
+			_ = os.Remove(dst)
+			return "", fmt.Errorf("name file: %w", err)
+		}
+		if err := syncDir(dir); err != nil {
+			return "", err
+		}
+		return dst, nil
 	}
+	return "", fmt.Errorf("name file: no free name for %s", name)
+}
 
-	written, err := io.Copy(out, reader)
+// syncDir flushes the entries of dir, so a rename survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
 	if err != nil {
-		_ = os.Remove(dstPath)
-		return "", fmt.Errorf("write file: %w", err)
+		return fmt.Errorf("open dir: %w", err)
 	}
-	if in.MaxBytes > 0 && written > in.MaxBytes {
-		_ = os.Remove(dstPath)
-		return "", fmt.Errorf("file too large: wrote %d bytes (max %d)", written, in.MaxBytes)
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("sync dir: %w", err)
 	}
-
-	return dstPath, nil
+	return nil
 }
 
 func sanitizeFilename(name string) string {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -28,3 +30,65 @@ func TestSaveIncomingFile_MaxBytes(t *testing.T) {
 		t.Fatalf("expected file too large error, got: %v", err)
 	}
 }
+
+func TestSaveIncomingFile_Hash(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	saved, err := SaveIncomingFile(context.Background(), SaveInput{
+		FileURL:      srv.URL,
+		DataDir:      t.TempDir(),
+		ChatID:       1,
+		OriginalName: "file.csv",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("unexpected hash: %s", saved.SHA256)
+	}
+}
+
+func TestSaveIncomingFile_NeverOverwrites(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Query().Get("body")))
+	}))
+	defer srv.Close()
+
+	dataDir := t.TempDir()
+	var paths []string
+	for _, body := range []string{"first", "second", "third"} {
+		saved, err := SaveIncomingFile(context.Background(), SaveInput{
+			FileURL:      srv.URL + "?body=" + body,
+			DataDir:      dataDir,
+			ChatID:       1,
+			OriginalName: "sales.csv",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved.Size != int64(len(body)) {
+			t.Fatalf("expected size %d, got %d", len(body), saved.Size)
+		}
+		paths = append(paths, saved.Path)
+	}
+
+	for i, body := range []string{"first", "second", "third"} {
+		data, err := os.ReadFile(paths[i])
+		if err != nil || string(data) != body {
+			t.Fatalf("file %d: expected %q, got %q (%v)", i, body, data, err)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dataDir, "incoming"))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			t.Fatalf("temp file left behind: %s", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotExist is returned by Store.Get for a missing key.
+var ErrNotExist = errors.New("object does not exist")
+
+// Object describes a stored blob. List does not fill Meta.
+type Object struct {
+	Key      string
+	Size     int64
+	Modified time.Time
+	Meta     map[string]string
+}
+
+// Store keeps blobs under slash separated keys such as
+// "archive/42/2026-02-06/upload.csv". Delete of a missing key is not an error.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, meta map[string]string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	List(ctx context.Context, prefix string) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStore keeps objects as files under a root directory. Metadata goes
+// to a "<file>.meta.json" sidecar next to the object.
+type LocalStore struct {
+	root string
+}
+
+func NewLocalStore(root string) *LocalStore {
+	return &LocalStore{root: root}
+}
+
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, meta map[string]string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(dst)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+
+	if len(meta) > 0 {
+		data, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode metadata: %w", err)
+		}
+		if err := writeAtomic(dir, dst+metaSuffix, strings.NewReader(string(data))); err != nil {
+			return err
+		}
+	} else {
+		_ = os.Remove(dst + metaSuffix)
+	}
+	return writeAtomic(dir, dst, r)
+}
+
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, Object, error) {
+	src, err := s.path(key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	f, err := os.Open(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Object{}, ErrNotExist
+	}
+	if err != nil {
+		return nil, Object{}, fmt.Errorf("open %s: %w", key, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, Object{}, fmt.Errorf("stat %s: %w", key, err)
+	}
+
+	obj := Object{Key: key, Size: info.Size(), Modified: info.ModTime()}
+	data, err := os.ReadFile(src + metaSuffix)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &obj.Meta); err != nil {
+			_ = f.Close()
+			return nil, Object{}, fmt.Errorf("decode metadata of %s: %w", key, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		_ = f.Close()
+		return nil, Object{}, fmt.Errorf("read metadata of %s: %w", key, err)
+	}
+	return f, obj, nil
+}
+
+func (s *LocalStore) List(_ context.Context, prefix string) ([]Object, error) {
+	// Walk the deepest directory the prefix names, then filter by key.
+	dirKey := prefix[:strings.LastIndex(prefix, "/")+1]
+	dir, err := s.path(dirKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, metaSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: key, Size: info.Size(), Modified: info.ModTime()})
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", prefix, err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+	_ = os.Remove(p + metaSuffix)
+	// Drop parent directories left empty, but never the root itself.
+	for dir := filepath.Dir(p); dir != filepath.Clean(s.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func validateKey(key string) error {
+	if strings.HasPrefix(key, "/") || path.Clean("/"+key) != "/"+strings.TrimSuffix(key, "/") {
+		return fmt.Errorf("invalid key: %q", key)
+	}
+	return nil
+}
+
+func writeAtomic(dir, dst string, r io.Reader) error {
+	tmp, err := os.CreateTemp(dir, ".put-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", filepath.Base(dst), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", filepath.Base(dst), err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("rename %s: %w", filepath.Base(dst), err)
+	}
+	return nil
+}
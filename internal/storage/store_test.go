@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testStoreRoundTrip runs the behaviour every Store implementation shares.
+// overhead is how much larger a stored object is than the data put in.
+func testStoreRoundTrip(t *testing.T, store Store, overhead int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, key := range []string{"archive/1/2026-02-06/a.csv", "archive/1/2026-02-07/b.csv", "archive/2/2026-02-07/c.csv", "reports/1/000001.json"} {
+		if err := store.Put(ctx, key, strings.NewReader("data of "+key), map[string]string{"uploader": "Jana Nováková"}); err != nil {
+			t.Fatalf("put %s: %v", key, err)
+		}
+	}
+
+	body, obj, err := store.Get(ctx, "archive/1/2026-02-07/b.csv")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	if string(data) != "data of archive/1/2026-02-07/b.csv" || obj.Meta["uploader"] != "Jana Nováková" {
+		t.Fatalf("unexpected object: %q %+v", data, obj)
+	}
+	if _, _, err := store.Get(ctx, "archive/9/missing.csv"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got: %v", err)
+	}
+
+	objects, err := store.List(ctx, "archive/1/")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(objects) != 2 || objects[0].Key != "archive/1/2026-02-06/a.csv" || objects[1].Size != int64(len(data))+overhead {
+		t.Fatalf("unexpected list: %+v", objects)
+	}
+	if all, err := store.List(ctx, "archive/"); err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 archived objects, got %d (%v)", len(all), err)
+	}
+
+	if err := store.Delete(ctx, "archive/1/2026-02-06/a.csv"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := store.Delete(ctx, "archive/1/2026-02-06/a.csv"); err != nil {
+		t.Fatalf("delete of a missing key: %v", err)
+	}
+	if objects, _ := store.List(ctx, "archive/1/"); len(objects) != 1 {
+		t.Fatalf("expected 1 object after delete, got: %+v", objects)
+	}
+}
+
+func TestLocalStore_RoundTrip(t *testing.T) {
+	testStoreRoundTrip(t, NewLocalStore(t.TempDir()), 0)
+}
+
+func TestLocalStore_SidecarAndCleanup(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalStore(root)
+	ctx := context.Background()
+
+	key := "archive/1/2026-02-06/a.csv"
+	if err := store.Put(ctx, key, strings.NewReader("a"), map[string]string{"sha256": "abc"}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "archive", "1", "2026-02-06", "a.csv"+metaSuffix)); err != nil {
+		t.Fatalf("expected metadata sidecar: %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "archive")); !os.IsNotExist(err) {
+		t.Fatalf("expected empty directories to be removed, got: %v", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Fatalf("expected root to stay: %v", err)
+	}
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	for _, key := range []string{"../etc/passwd", "/abs", "a/../../b"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), nil); err == nil {
+			t.Fatalf("%q: expected invalid key error", key)
+		}
+	}
+}